@@ -0,0 +1,122 @@
+package fauxgl
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"sort"
+)
+
+// Atlas 纹理图集，将多个图像打包到一个图像中
+type Atlas struct {
+	Image *image.NRGBA      // 图集图像
+	Rects []image.Rectangle // 各源图像在图集中所占的矩形（不含填充）
+}
+
+// NewAtlas 使用货架算法将图像打包为图集
+// padding 是每个图像周围的填充像素数，填充区域复制边缘像素以避免双线性过滤时颜色渗透
+func NewAtlas(images []image.Image, padding int) *Atlas {
+	// 按高度降序排列以减少浪费
+	order := make([]int, len(images))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return images[order[i]].Bounds().Dy() > images[order[j]].Bounds().Dy()
+	})
+
+	// 图集宽度取不小于总面积平方根和最宽图像的 2 的幂
+	var area, maxWidth int
+	for _, im := range images {
+		w := im.Bounds().Dx() + padding*2
+		h := im.Bounds().Dy() + padding*2
+		area += w * h
+		if w > maxWidth {
+			maxWidth = w
+		}
+	}
+	width := 1
+	for width < maxWidth || width*width < area {
+		width *= 2
+	}
+
+	// 逐行放置
+	rects := make([]image.Rectangle, len(images))
+	var x, y, rowHeight int
+	for _, i := range order {
+		w := images[i].Bounds().Dx() + padding*2
+		h := images[i].Bounds().Dy() + padding*2
+		if x+w > width {
+			x = 0
+			y += rowHeight
+			rowHeight = 0
+		}
+		rects[i] = image.Rect(x+padding, y+padding, x+w-padding, y+h-padding)
+		x += w
+		if h > rowHeight {
+			rowHeight = h
+		}
+	}
+	height := y + rowHeight
+
+	// 绘制图像和填充
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for i, im := range images {
+		r := rects[i]
+		src := im.Bounds()
+		draw.Draw(dst, r, im, src.Min, draw.Src)
+		for py := r.Min.Y - padding; py < r.Max.Y+padding; py++ {
+			for px := r.Min.X - padding; px < r.Max.X+padding; px++ {
+				if image.Pt(px, py).In(r) {
+					continue
+				}
+				sx := ClampInt(px, r.Min.X, r.Max.X-1)
+				sy := ClampInt(py, r.Min.Y, r.Max.Y-1)
+				dst.SetNRGBA(px, py, dst.NRGBAAt(sx, sy))
+			}
+		}
+	}
+
+	return &Atlas{dst, rects}
+}
+
+// Transform 将第 index 个源图像的纹理坐标变换为图集中的纹理坐标
+// 纹理坐标应位于 [0, 1] 范围内，图集无法保留重复环绕
+func (a *Atlas) Transform(index int, uv Vector) Vector {
+	size := a.Image.Bounds().Size()
+	r := a.Rects[index]
+	u := Clamp(uv.X, 0, 1)
+	v := Clamp(uv.Y, 0, 1)
+	x := float64(r.Min.X) + u*float64(r.Dx())
+	y := float64(r.Min.Y) + (1-v)*float64(r.Dy())
+	return Vector{x / float64(size.X), 1 - y/float64(size.Y), uv.Z}
+}
+
+// RemapMesh 将网格的纹理坐标重映射到第 index 个源图像在图集中的子矩形
+func (a *Atlas) RemapMesh(mesh *Mesh, index int) {
+	for _, t := range mesh.Triangles {
+		t.V1.Texture = a.Transform(index, t.V1.Texture)
+		t.V2.Texture = a.Transform(index, t.V2.Texture)
+		t.V3.Texture = a.Transform(index, t.V3.Texture)
+	}
+	for _, l := range mesh.Lines {
+		l.V1.Texture = a.Transform(index, l.V1.Texture)
+		l.V2.Texture = a.Transform(index, l.V2.Texture)
+	}
+}
+
+// BuildAtlas 将每个网格的纹理图像打包为图集，并返回纹理坐标已重映射的合并网格和图集图像
+// meshes[i] 使用 images[i] 作为纹理，源网格不会被修改
+func BuildAtlas(meshes []*Mesh, images []image.Image, padding int) (*Mesh, image.Image, error) {
+	if len(meshes) != len(images) {
+		return nil, nil, fmt.Errorf("atlas requires one image per mesh, got %d meshes and %d images", len(meshes), len(images))
+	}
+	atlas := NewAtlas(images, padding)
+	result := NewEmptyMesh()
+	for i, mesh := range meshes {
+		mesh = mesh.Copy()
+		atlas.RemapMesh(mesh, i)
+		result.Add(mesh)
+	}
+	return result, atlas.Image, nil
+}
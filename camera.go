@@ -0,0 +1,145 @@
+package fauxgl
+
+import "math"
+
+// Camera 相机，包含位置、朝向和投影参数
+type Camera struct {
+	Position     Vector  // 相机位置
+	Target       Vector  // 观察目标点，也是环绕的中心
+	Up           Vector  // 向上方向
+	Fovy         float64 // 透视投影的垂直视场角（度）
+	Orthographic bool    // 是否使用正交投影
+	OrthoSize    float64 // 正交投影的视图半高
+	Aspect       float64 // 宽高比
+	Near         float64 // 近平面距离
+	Far          float64 // 远平面距离
+}
+
+// NewPerspectiveCamera 创建透视投影相机
+func NewPerspectiveCamera(position, target, up Vector, fovy, aspect float64) *Camera {
+	return &Camera{position, target, up, fovy, false, 1, aspect, 1, 100}
+}
+
+// NewOrthographicCamera 创建正交投影相机，size 是视图的半高
+func NewOrthographicCamera(position, target, up Vector, size, aspect float64) *Camera {
+	return &Camera{position, target, up, 30, true, size, aspect, 1, 100}
+}
+
+// View 返回将世界坐标变换到视空间的观察矩阵
+func (c *Camera) View() Matrix {
+	return LookAt(c.Position, c.Target, c.Up)
+}
+
+// Projection 返回投影矩阵
+func (c *Camera) Projection() Matrix {
+	if c.Orthographic {
+		h := c.OrthoSize
+		w := h * c.Aspect
+		return Orthographic(-w, w, -h, h, c.Near, c.Far)
+	}
+	return Perspective(c.Fovy, c.Aspect, c.Near, c.Far)
+}
+
+// Matrix 返回将世界坐标变换到裁剪坐标的矩阵，即投影矩阵与观察矩阵的乘积
+func (c *Camera) Matrix() Matrix {
+	return c.Projection().Mul(c.View())
+}
+
+// Forward 返回相机的观察方向
+func (c *Camera) Forward() Vector {
+	return c.Target.Sub(c.Position).Normalize()
+}
+
+// Right 返回相机的右方向
+func (c *Camera) Right() Vector {
+	return c.Forward().Cross(c.Up).Normalize()
+}
+
+// Distance 返回相机到观察目标点的距离
+func (c *Camera) Distance() float64 {
+	return c.Position.Distance(c.Target)
+}
+
+// Orbit 使相机绕观察目标点旋转，单位为弧度
+// yaw 为正时相机向右环绕，pitch 为正时相机向上环绕；俯仰被限制在向上方向的两侧之间，不会翻转
+func (c *Camera) Orbit(yaw, pitch float64) {
+	up := c.Up.Normalize()
+	offset := c.Position.Sub(c.Target)
+	offset = Rotate(up, -yaw).MulPosition(offset)
+	// 向上环绕会减小偏移与向上方向的夹角，限制旋转后的夹角在 [eps, pi - eps] 内
+	const eps = 1e-3
+	d := offset.Length()
+	angle := math.Acos(Clamp(offset.Dot(up)/d, -1, 1))
+	pitch = Clamp(pitch, angle-math.Pi+eps, angle-eps)
+	right := up.Cross(offset).Normalize()
+	offset = Rotate(right, pitch).MulPosition(offset)
+	c.Position = c.Target.Add(offset)
+}
+
+// Dolly 使相机沿观察方向靠近或远离观察目标点，到目标点的距离乘以 factor
+// 正交投影时视图大小同时缩放，以获得相同的缩放效果
+func (c *Camera) Dolly(factor float64) {
+	offset := c.Position.Sub(c.Target)
+	c.Position = c.Target.Add(offset.MulScalar(factor))
+	if c.Orthographic {
+		c.OrthoSize *= factor
+	}
+}
+
+// Pan 在视平面内平移相机和观察目标点，dx 沿右方向，dy 沿屏幕向上方向，单位为世界坐标
+func (c *Camera) Pan(dx, dy float64) {
+	right := c.Right()
+	up := right.Cross(c.Forward())
+	d := right.MulScalar(dx).Add(up.MulScalar(dy))
+	c.Position = c.Position.Add(d)
+	c.Target = c.Target.Add(d)
+}
+
+// FitToBox 保持观察方向不变，移动相机使整个包围盒位于视野中，并根据包围盒设置紧凑的近平面和远平面
+func (c *Camera) FitToBox(box Box) {
+	center := box.Center()
+	radius := box.Size().Length() / 2
+	if radius == 0 {
+		radius = 1
+	}
+	forward := c.Forward()
+
+	// 包围球同时适合垂直和水平视场
+	var distance float64
+	if c.Orthographic {
+		c.OrthoSize = math.Max(radius, radius/c.Aspect)
+		distance = radius * 2
+	} else {
+		fovy := Radians(c.Fovy)
+		fovx := 2 * math.Atan(math.Tan(fovy/2)*c.Aspect)
+		distance = radius / math.Sin(math.Min(fovy, fovx)/2)
+	}
+	c.Target = center
+	c.Position = center.Sub(forward.MulScalar(distance))
+
+	// 根据包围盒角点的视空间深度确定近平面和远平面
+	view := c.View()
+	near := math.MaxFloat64
+	far := -math.MaxFloat64
+	for i := 0; i < 8; i++ {
+		p := Vector{box.Min.X, box.Min.Y, box.Min.Z}
+		if i&1 != 0 {
+			p.X = box.Max.X
+		}
+		if i&2 != 0 {
+			p.Y = box.Max.Y
+		}
+		if i&4 != 0 {
+			p.Z = box.Max.Z
+		}
+		z := -view.MulPosition(p).Z
+		near = math.Min(near, z)
+		far = math.Max(far, z)
+	}
+	margin := (far - near) * 0.01
+	if margin == 0 {
+		margin = radius * 0.01
+	}
+	c.Near = math.Max(near-margin, far*1e-4)
+	c.Far = far + margin
+}
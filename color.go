@@ -30,7 +30,14 @@ func MakeColor(c color.Color) Color {
 	return Color{float64(r) / d, float64(g) / d, float64(b) / d, float64(a) / d}
 }
 
+// colorFromNRGBA 由非预乘的 NRGBA 颜色生成颜色
+func colorFromNRGBA(c color.NRGBA) Color {
+	const d = 0xff
+	return Color{float64(c.R) / d, float64(c.G) / d, float64(c.B) / d, float64(c.A) / d}
+}
+
 // HexColor 生成十六进制颜色
+// 十六进制颜色通常是 sRGB 编码的，在线性空间中计算光照时应调用 Linear 进行转换
 func HexColor(x string) Color {
 	x = strings.Trim(x, "#")
 	var r, g, b, a int
@@ -135,3 +142,30 @@ func (a Color) Min(b Color) Color {
 func (a Color) Max(b Color) Color {
 	return Color{math.Max(a.R, b.R), math.Max(a.G, b.G), math.Max(a.B, b.B), math.Max(a.A, b.A)}
 }
+
+// srgbToLinear 将 sRGB 编码的分量转换为线性分量
+func srgbToLinear(x float64) float64 {
+	if x <= 0.04045 {
+		return x / 12.92
+	}
+	return math.Pow((x+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB 将线性分量编码为 sRGB 分量，结果被限制在 [0, 1] 范围内
+func linearToSRGB(x float64) float64 {
+	x = Clamp(x, 0, 1)
+	if x <= 0.0031308 {
+		return x * 12.92
+	}
+	return 1.055*math.Pow(x, 1/2.4) - 0.055
+}
+
+// Linear 将 sRGB 编码的颜色转换为线性颜色，透明度保持不变
+func (a Color) Linear() Color {
+	return Color{srgbToLinear(a.R), srgbToLinear(a.G), srgbToLinear(a.B), a.A}
+}
+
+// SRGB 将线性颜色编码为 sRGB 颜色，透明度保持不变
+func (a Color) SRGB() Color {
+	return Color{linearToSRGB(a.R), linearToSRGB(a.G), linearToSRGB(a.B), a.A}
+}
@@ -0,0 +1,112 @@
+package fauxgl
+
+import "math"
+
+// Colormap 颜色映射，将 [0, 1] 范围内的值映射为颜色
+// 各颜色节点在 [0, 1] 范围内等距分布
+type Colormap struct {
+	Colors []Color // 颜色节点
+}
+
+// NewColormap 由等距分布的颜色节点创建颜色映射（自定义渐变）
+func NewColormap(colors ...Color) *Colormap {
+	return &Colormap{colors}
+}
+
+// NewHexColormap 由十六进制颜色节点创建颜色映射
+func NewHexColormap(colors ...string) *Colormap {
+	result := make([]Color, len(colors))
+	for i, x := range colors {
+		result[i] = HexColor(x)
+	}
+	return NewColormap(result...)
+}
+
+var (
+	// Viridis 颜色映射
+	Viridis = NewHexColormap(
+		"#440154", "#482878", "#3e4989", "#31688e", "#26828e",
+		"#1f9e89", "#35b779", "#6ece58", "#b5de2b", "#fde725")
+	// Jet 颜色映射
+	Jet = NewHexColormap(
+		"#00007f", "#0000ff", "#007fff", "#00ffff", "#7fff7f",
+		"#ffff00", "#ff7f00", "#ff0000", "#7f0000")
+	// Coolwarm 发散型颜色映射
+	Coolwarm = NewHexColormap(
+		"#3b4cc0", "#7b9ff9", "#dddddd", "#f49a7b", "#b40426")
+)
+
+// At 返回 t 处的颜色，t 被限制在 [0, 1] 范围内，NaN 视为 0
+func (m *Colormap) At(t float64) Color {
+	n := len(m.Colors)
+	if n == 0 {
+		return Black
+	}
+	if n == 1 {
+		return m.Colors[0]
+	}
+	if math.IsNaN(t) {
+		t = 0
+	}
+	t = Clamp(t, 0, 1) * float64(n-1)
+	i := int(t)
+	if i >= n-1 {
+		return m.Colors[n-1]
+	}
+	return m.Colors[i].Lerp(m.Colors[i+1], t-float64(i))
+}
+
+// ScalarShader 按插值后的顶点标量 Vertex.Scalar 通过颜色映射着色
+type ScalarShader struct {
+	Matrix         Matrix
+	LightDirection Vector // 光照方向，零向量表示不使用光照
+	AmbientColor   Color
+	DiffuseColor   Color
+	Colormap       *Colormap // 颜色映射
+	Min, Max       float64   // 映射到颜色映射两端的标量值
+	Bands          int       // 色带数量，<= 0 表示连续着色
+	IsolineSpacing float64   // 等值线间距（标量单位），<= 0 表示不绘制等值线
+	IsolineWidth   float64   // 等值线宽度，占等值线间距的比例
+	IsolineColor   Color     // 等值线颜色
+}
+
+// NewScalarShader 创建一个标量场着色器
+func NewScalarShader(matrix Matrix, colormap *Colormap, min, max float64) *ScalarShader {
+	ambient := Color{0.4, 0.4, 0.4, 1}
+	diffuse := Color{0.6, 0.6, 0.6, 1}
+	return &ScalarShader{
+		matrix, Vector{}, ambient, diffuse,
+		colormap, min, max, 0, 0, 0.05, Black}
+}
+
+// Vertex 顶点着色器
+func (shader *ScalarShader) Vertex(v Vertex) Vertex {
+	v.Output = shader.Matrix.MulPositionW(v.Position)
+	return v
+}
+
+// Fragment 片元着色器
+func (shader *ScalarShader) Fragment(v Vertex) Color {
+	if shader.IsolineSpacing > 0 {
+		f := v.Scalar / shader.IsolineSpacing
+		if math.Abs(f-math.Floor(f+0.5)) < shader.IsolineWidth/2 {
+			return shader.IsolineColor
+		}
+	}
+	// Min 与 Max 相等时（例如常数标量场）映射到颜色映射的起点
+	var t float64
+	if shader.Max != shader.Min {
+		t = (v.Scalar - shader.Min) / (shader.Max - shader.Min)
+	}
+	if shader.Bands > 0 {
+		n := float64(shader.Bands)
+		t = (math.Min(math.Floor(t*n), n-1) + 0.5) / n
+	}
+	color := shader.Colormap.At(t)
+	if shader.LightDirection == (Vector{}) {
+		return color
+	}
+	diffuse := math.Max(v.Normal.Dot(shader.LightDirection), 0)
+	light := shader.AmbientColor.Add(shader.DiffuseColor.MulScalar(diffuse))
+	return color.Mul(light).Min(White).Alpha(color.A)
+}
@@ -0,0 +1,137 @@
+package fauxgl
+
+// VertexModifier 顶点修饰器，在被包装的着色器变换之前修改输入顶点
+type VertexModifier func(v Vertex) Vertex
+
+// FragmentModifier 片元修饰器，修改被包装的着色器输出的颜色
+// v 为插值后的顶点，返回 Discard 表示丢弃该片元
+type FragmentModifier func(v Vertex, c Color) Color
+
+// ComposedShader 在一个着色器外层叠加顶点修饰器和片元修饰器
+type ComposedShader struct {
+	Shader    Shader             // 被包装的着色器
+	Vertexes  []VertexModifier   // 顶点修饰器，按顺序执行
+	Fragments []FragmentModifier // 片元修饰器，按顺序执行
+}
+
+// Compose 创建一个包装 shader 的组合着色器
+func Compose(shader Shader) *ComposedShader {
+	return &ComposedShader{shader, nil, nil}
+}
+
+// WithVertex 追加顶点修饰器，返回着色器本身以便链式调用
+func (shader *ComposedShader) WithVertex(modifiers ...VertexModifier) *ComposedShader {
+	shader.Vertexes = append(shader.Vertexes, modifiers...)
+	return shader
+}
+
+// WithFragment 追加片元修饰器，返回着色器本身以便链式调用
+func (shader *ComposedShader) WithFragment(modifiers ...FragmentModifier) *ComposedShader {
+	shader.Fragments = append(shader.Fragments, modifiers...)
+	return shader
+}
+
+// Vertex 顶点着色器
+func (shader *ComposedShader) Vertex(v Vertex) Vertex {
+	for _, m := range shader.Vertexes {
+		v = m(v)
+	}
+	return shader.Shader.Vertex(v)
+}
+
+// Fragment 片元着色器
+func (shader *ComposedShader) Fragment(v Vertex) Color {
+	return shader.modify(v, shader.Shader.Fragment(v))
+}
+
+// Derivatives 被包装的着色器需要偏导数时返回 true
+func (shader *ComposedShader) Derivatives() bool {
+	ds, ok := shader.Shader.(DerivativeShader)
+	return ok && ds.Derivatives()
+}
+
+// FragmentDerivatives 调用被包装的着色器的 FragmentDerivatives，再执行片元修饰器
+func (shader *ComposedShader) FragmentDerivatives(v, right, below Vertex) Color {
+	ds := shader.Shader.(DerivativeShader)
+	return shader.modify(v, ds.FragmentDerivatives(v, right, below))
+}
+
+// modify 依次对颜色执行片元修饰器
+func (shader *ComposedShader) modify(v Vertex, color Color) Color {
+	for _, m := range shader.Fragments {
+		if color == Discard {
+			break
+		}
+		color = m(v, color)
+	}
+	return color
+}
+
+// TransformVertex 返回一个使用 matrix 变换顶点位置和法线的顶点修饰器
+func TransformVertex(matrix Matrix) VertexModifier {
+	return func(v Vertex) Vertex {
+		v.Position = matrix.MulPosition(v.Position)
+		v.Normal = matrix.MulDirection(v.Normal)
+		return v
+	}
+}
+
+// InflateVertex 返回一个将顶点沿法线方向移动 distance 的顶点修饰器
+func InflateVertex(distance float64) VertexModifier {
+	return func(v Vertex) Vertex {
+		v.Position = v.Position.Add(v.Normal.MulScalar(distance))
+		return v
+	}
+}
+
+// MultiplyColor 返回一个将颜色乘以 c 的片元修饰器
+func MultiplyColor(c Color) FragmentModifier {
+	return func(v Vertex, color Color) Color {
+		return color.Mul(c)
+	}
+}
+
+// AddColor 返回一个将颜色加上 c 的片元修饰器，透明度保持不变
+func AddColor(c Color) FragmentModifier {
+	return func(v Vertex, color Color) Color {
+		return color.Add(c).Alpha(color.A)
+	}
+}
+
+// DiscardIf 返回一个在 f 返回 true 时丢弃片元的片元修饰器
+func DiscardIf(f func(v Vertex, c Color) bool) FragmentModifier {
+	return func(v Vertex, color Color) Color {
+		if f(v, color) {
+			return Discard
+		}
+		return color
+	}
+}
+
+// ClipByPlane 返回一个丢弃平面背面片元的片元修饰器
+// 平面经过点 point，法线 normal 指向保留的一侧
+func ClipByPlane(point, normal Vector) FragmentModifier {
+	return DiscardIf(func(v Vertex, c Color) bool {
+		return v.Position.Sub(point).Dot(normal) < 0
+	})
+}
+
+// BackFaceColor 返回一个将背对视点 eye 的片元替换为颜色 c 的片元修饰器
+// 与 ClipByPlane 和 CullNone 一起使用可以为剖面着色
+func BackFaceColor(eye Vector, c Color) FragmentModifier {
+	return func(v Vertex, color Color) Color {
+		if v.Normal.Dot(eye.Sub(v.Position)) < 0 {
+			return c
+		}
+		return color
+	}
+}
+
+// FogModifier 返回一个按视空间深度混合雾色的片元修饰器
+// view 将顶点位置变换到视空间
+func FogModifier(view Matrix, fog *Fog) FragmentModifier {
+	return func(v Vertex, color Color) Color {
+		depth := -view.MulPosition(v.Position).Z
+		return fog.Blend(color, depth)
+	}
+}
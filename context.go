@@ -50,23 +50,37 @@ func (info RasterizeInfo) Add(other RasterizeInfo) RasterizeInfo {
 
 // Context 是一个渲染上下文，包含颜色缓冲区、深度缓冲区、清除颜色、着色器、深度测试、颜色混合、线框模式、剔除模式、线宽、深度偏移、屏幕矩阵和锁等属性
 type Context struct {
-	Width        int          // 宽度
-	Height       int          // 高度
-	ColorBuffer  *image.NRGBA // 颜色缓冲区
-	DepthBuffer  []float64    // 深度缓冲区
-	ClearColor   Color        // 清除颜色
-	Shader       Shader       // 着色器
-	ReadDepth    bool         // 深度测试
-	WriteDepth   bool         // 深度测试
-	WriteColor   bool         // 颜色混合
-	AlphaBlend   bool         // 颜色混合
-	Wireframe    bool         // 线框模式
-	FrontFace    Face         // 剔除模式
-	Cull         Cull         // 剔除模式
-	LineWidth    float64      // 线宽
-	DepthBias    float64      // 深度偏移
-	screenMatrix Matrix       // 屏幕矩阵
-	locks        []sync.Mutex // 锁
+	Width           int          // 宽度
+	Height          int          // 高度
+	ColorBuffer     *image.NRGBA // 颜色缓冲区
+	DepthBuffer     []float64    // 深度缓冲区
+	ObjectBuffer    []int32      // 对象缓冲区，记录每个像素最近的对象编号，0 表示背景；首次使用非零对象编号或几何缓冲区时分配
+	ObjectID        int          // 当前绘制对象的编号，与深度一起写入对象缓冲区
+	GBuffer         *GBuffer     // 几何缓冲区，非 nil 时与深度一起写入法线和纹理坐标
+	ClearColor      Color        // 清除颜色
+	Shader          Shader       // 着色器
+	ReadDepth       bool         // 深度测试
+	WriteDepth      bool         // 深度测试
+	WriteColor      bool         // 颜色混合
+	AlphaBlend      bool         // 颜色混合
+	Transparency    Transparency // 透明片元的混合模式
+	SRGBOutput      bool         // 着色器输出线性颜色，写入颜色缓冲区时编码为 sRGB
+	HDR             bool         // 将着色器输出的线性颜色不经截断地写入 HDRBuffer 而不是颜色缓冲区
+	HDRBuffer       []Color      // 高动态范围缓冲区，首次使用 HDR 模式时分配
+	AlphaTest       float64      // 透明度阈值，透明度低于该值的片元被丢弃且不写入深度
+	AlphaToCoverage bool         // 将透明度转换为覆盖率，配合超采样使用
+	Wireframe       bool         // 线框模式
+	FlatShading     bool         // 平面着色，使用三角形面法线代替顶点法线
+	FrontFace       Face         // 剔除模式
+	Cull            Cull         // 剔除模式
+	LineWidth       float64      // 线宽
+	DepthBias       float64      // 深度偏移
+	screenMatrix    Matrix       // 屏幕矩阵
+	locks           []sync.Mutex // 锁
+	oit             []oitSample  // 顺序无关透明的累积缓冲区
+	oitOnce         sync.Once    // 用于分配累积缓冲区
+	hdrOnce         sync.Once    // 用于分配高动态范围缓冲区
+	objectOnce      sync.Once    // 用于分配对象缓冲区
 }
 
 // NewContext 创建一个新的渲染上下文
@@ -77,12 +91,19 @@ func NewContext(width, height int) *Context {
 	dc.ColorBuffer = image.NewNRGBA(image.Rect(0, 0, width, height))
 	dc.DepthBuffer = make([]float64, width*height)
 	dc.ClearColor = Transparent
+	dc.ObjectID = 0
 	dc.Shader = NewSolidColorShader(Identity(), Color{1, 0, 1, 1})
 	dc.ReadDepth = true
 	dc.WriteDepth = true
 	dc.WriteColor = true
 	dc.AlphaBlend = true
+	dc.Transparency = TransparencyOrdered
+	dc.SRGBOutput = false
+	dc.HDR = false
+	dc.AlphaTest = 0
+	dc.AlphaToCoverage = false
 	dc.Wireframe = false
+	dc.FlatShading = false
 	dc.FrontFace = FaceCCW
 	dc.Cull = CullBack
 	dc.LineWidth = 2
@@ -131,8 +152,17 @@ func (dc *Context) DepthImage() image.Image {
 	return im
 }
 
-// ClearColorBufferWith 使用指定颜色清除颜色缓冲区
+// ClearColorBufferWith 使用指定颜色清除颜色缓冲区，HDR 模式下同时清除高动态范围缓冲区
 func (dc *Context) ClearColorBufferWith(color Color) {
+	if dc.HDR {
+		buffer := dc.hdrBuffer()
+		for i := range buffer {
+			buffer[i] = color
+		}
+	}
+	if dc.SRGBOutput {
+		color = color.SRGB()
+	}
 	c := color.NRGBA()
 	for y := 0; y < dc.Height; y++ {
 		i := dc.ColorBuffer.PixOffset(0, y)
@@ -151,11 +181,17 @@ func (dc *Context) ClearColorBuffer() {
 	dc.ClearColorBufferWith(dc.ClearColor)
 }
 
-// ClearDepthBufferWith 使用指定值清除深度缓冲区
+// ClearDepthBufferWith 使用指定值清除深度缓冲区，对象缓冲区和几何缓冲区同时被清除
 func (dc *Context) ClearDepthBufferWith(value float64) {
 	for i := range dc.DepthBuffer {
 		dc.DepthBuffer[i] = value
 	}
+	for i := range dc.ObjectBuffer {
+		dc.ObjectBuffer[i] = 0
+	}
+	if dc.GBuffer != nil {
+		dc.GBuffer.clear()
+	}
 }
 
 // ClearDepthBuffer 使用最大值清除深度缓冲区
@@ -163,6 +199,31 @@ func (dc *Context) ClearDepthBuffer() {
 	dc.ClearDepthBufferWith(math.MaxFloat64)
 }
 
+// unproject 将像素 (x, y) 处的屏幕深度 z 还原到 inverse 所表示的空间
+// inverse 通常是投影矩阵的逆矩阵，此时返回视空间坐标
+func (dc *Context) unproject(x, y int, z float64, inverse Matrix) Vector {
+	ndc := Vector{
+		(float64(x)+0.5)/float64(dc.Width)*2 - 1,
+		1 - (float64(y)+0.5)/float64(dc.Height)*2,
+		z*2 - 1,
+	}
+	p := inverse.MulPositionW(ndc)
+	return p.DivScalar(p.W).Vector()
+}
+
+// bayerMatrix 4x4 有序抖动矩阵
+var bayerMatrix = [16]float64{
+	0, 8, 2, 10,
+	12, 4, 14, 6,
+	3, 11, 1, 9,
+	15, 7, 13, 5,
+}
+
+// bayerThreshold 返回像素 (x, y) 处的抖动阈值，位于 (0, 1) 范围内
+func bayerThreshold(x, y int) float64 {
+	return (bayerMatrix[(y&3)*4+(x&3)] + 0.5) / 16
+}
+
 // edge 计算三角形的边
 func edge(a, b, c Vector) float64 {
 	return (b.X-c.X)*(a.Y-c.Y) - (b.Y-c.Y)*(a.X-c.X)
@@ -201,6 +262,21 @@ func (dc *Context) rasterize(v0, v1, v2 Vertex, s0, s1, s2 Vector) RasterizeInfo
 	ra20 := 1 / a20
 	ra01 := 1 / a01
 
+	// 需要屏幕空间偏导数的着色器
+	ds, derivatives := dc.Shader.(DerivativeShader)
+	derivatives = derivatives && ds.Derivatives()
+
+	// 对象缓冲区只在使用非零对象编号时分配
+	objects := dc.ObjectBuffer
+	if dc.ObjectID != 0 {
+		objects = dc.objectBuffer()
+	}
+	interpolate := func(w0, w1, w2 float64) Vertex {
+		b := VectorW{w0 * ra * r0, w1 * ra * r1, w2 * ra * r2, 0}
+		b.W = 1 / (b.X + b.Y + b.Z)
+		return InterpolateVertexes(v0, v1, v2, b)
+	}
+
 	// 遍历边界框中的所有像素
 	for y := y0; y <= y1; y++ {
 		var d float64
@@ -258,23 +334,56 @@ func (dc *Context) rasterize(v0, v1, v2 Vertex, s0, s1, s2 Vector) RasterizeInfo
 			b.W = 1 / (b.X + b.Y + b.Z)
 			v := InterpolateVertexes(v0, v1, v2, b)
 			// 调用片段着色器
-			color := dc.Shader.Fragment(v)
+			var color Color
+			if derivatives {
+				// 此时 w0, w1, w2 已前进到像素 (x+1, y)
+				right := interpolate(w0, w1, w2)
+				below := interpolate(w0-a12+b12, w1-a20+b20, w2-a01+b01)
+				color = ds.FragmentDerivatives(v, right, below)
+			} else {
+				color = dc.Shader.Fragment(v)
+			}
 			if color == Discard {
 				continue
 			}
+			// 透明度测试
+			if color.A < dc.AlphaTest {
+				continue
+			}
+			if dc.AlphaToCoverage {
+				// 使用有序抖动将透明度转换为覆盖率，降采样后得到平滑的边缘
+				if color.A <= bayerThreshold(x, y) {
+					continue
+				}
+				color.A = 1
+			}
 			// 原子更新缓冲区
 			lock := &dc.locks[(x+y)&255]
 			lock.Lock()
 			// 再次检查深度缓冲区
 			if bz <= dc.DepthBuffer[i] || !dc.ReadDepth {
 				info.UpdatedPixels++
-				if dc.WriteDepth {
+				// 顺序无关透明模式下透明片元只累积颜色，不写入深度
+				transparent := dc.Transparency == TransparencyWeighted && color.A < 1
+				if dc.WriteDepth && !transparent {
 					// 更新深度缓冲区
 					dc.DepthBuffer[i] = z
+					if objects != nil {
+						objects[i] = int32(dc.ObjectID)
+					}
+					if dc.GBuffer != nil {
+						dc.GBuffer.write(i, v)
+					}
 				}
 				if dc.WriteColor {
 					// 更新颜色缓冲区
-					if dc.AlphaBlend && color.A < 1 {
+					if transparent {
+						dc.accumulateTransparent(i, z, color)
+					} else if dc.HDR {
+						dc.writeHDR(i, color)
+					} else if dc.SRGBOutput {
+						dc.writeLinear(x, y, color)
+					} else if dc.AlphaBlend && color.A < 1 {
 						sr, sg, sb, sa := color.NRGBA().RGBA()
 						a := (0xffff - sa) * 0x101
 						j := dc.ColorBuffer.PixOffset(x, y)
@@ -301,6 +410,49 @@ func (dc *Context) rasterize(v0, v1, v2 Vertex, s0, s1, s2 Vector) RasterizeInfo
 	return info
 }
 
+// writeLinear 在线性空间中将颜色混合到颜色缓冲区，并以 sRGB 编码写入
+func (dc *Context) writeLinear(x, y int, c Color) {
+	if dc.AlphaBlend && c.A < 1 {
+		d := colorFromNRGBA(dc.ColorBuffer.NRGBAAt(x, y)).Linear()
+		a := Clamp(c.A, 0, 1)
+		c = c.MulScalar(a).Add(d.MulScalar(1 - a))
+		c.A = a + d.A*(1-a)
+	}
+	dc.ColorBuffer.SetNRGBA(x, y, c.SRGB().NRGBA())
+}
+
+// hdrBuffer 返回高动态范围缓冲区，首次使用时分配
+func (dc *Context) hdrBuffer() []Color {
+	dc.hdrOnce.Do(func() {
+		if dc.HDRBuffer == nil {
+			dc.HDRBuffer = make([]Color, dc.Width*dc.Height)
+		}
+	})
+	return dc.HDRBuffer
+}
+
+// objectBuffer 返回对象缓冲区，首次使用时分配
+func (dc *Context) objectBuffer() []int32 {
+	dc.objectOnce.Do(func() {
+		if dc.ObjectBuffer == nil {
+			dc.ObjectBuffer = make([]int32, dc.Width*dc.Height)
+		}
+	})
+	return dc.ObjectBuffer
+}
+
+// writeHDR 将线性颜色混合到高动态范围缓冲区的像素 i 中
+func (dc *Context) writeHDR(i int, c Color) {
+	buffer := dc.hdrBuffer()
+	if dc.AlphaBlend && c.A < 1 {
+		d := buffer[i]
+		a := Clamp(c.A, 0, 1)
+		c = c.MulScalar(a).Add(d.MulScalar(1 - a))
+		c.A = a + d.A*(1-a)
+	}
+	buffer[i] = c
+}
+
 // line 绘制线段
 func (dc *Context) line(v0, v1 Vertex, s0, s1 Vector) RasterizeInfo {
 	n := s1.Sub(s0).Perpendicular().MulScalar(dc.LineWidth / 2)
@@ -395,10 +547,17 @@ func (dc *Context) DrawLine(t *Line) RasterizeInfo {
 
 // DrawTriangle 绘制三角形
 func (dc *Context) DrawTriangle(t *Triangle) RasterizeInfo {
+	v1, v2, v3 := t.V1, t.V2, t.V3
+	if dc.FlatShading {
+		// 使用面法线
+		n := t.Normal()
+		v1.Normal, v2.Normal, v3.Normal = n, n, n
+	}
+
 	// 调用顶点着色器
-	v1 := dc.Shader.Vertex(t.V1)
-	v2 := dc.Shader.Vertex(t.V2)
-	v3 := dc.Shader.Vertex(t.V3)
+	v1 = dc.Shader.Vertex(v1)
+	v2 = dc.Shader.Vertex(v2)
+	v3 = dc.Shader.Vertex(v3)
 
 	if v1.Outside() || v2.Outside() || v3.Outside() {
 		// 裁剪到视图体积
@@ -461,6 +620,22 @@ func (dc *Context) DrawTriangles(triangles []*Triangle) RasterizeInfo {
 	return result
 }
 
+// parallelFor 将 [0, n) 交错分配给多个协程并行执行 fn，所有调用返回后才返回
+func parallelFor(n int, fn func(i int)) {
+	wn := runtime.NumCPU()
+	var wg sync.WaitGroup
+	wg.Add(wn)
+	for wi := 0; wi < wn; wi++ {
+		go func(wi int) {
+			defer wg.Done()
+			for i := wi; i < n; i += wn {
+				fn(i)
+			}
+		}(wi)
+	}
+	wg.Wait()
+}
+
 // DrawMesh 绘制网格
 func (dc *Context) DrawMesh(mesh *Mesh) RasterizeInfo {
 	info1 := dc.DrawTriangles(mesh.Triangles)
@@ -0,0 +1,251 @@
+package fauxgl
+
+import (
+	"fmt"
+	"image"
+	"math"
+)
+
+// DirectionTexture 按三维方向采样的纹理
+type DirectionTexture interface {
+	// SampleDirection 对方向 d 进行采样
+	SampleDirection(d Vector) Color
+
+	// BilinearSampleDirection 使用双线性插值对方向 d 进行采样
+	BilinearSampleDirection(d Vector) Color
+}
+
+// 立方体贴图的面
+const (
+	CubePositiveX = iota // +X
+	CubeNegativeX        // -X
+	CubePositiveY        // +Y
+	CubeNegativeY        // -Y
+	CubePositiveZ        // +Z
+	CubeNegativeZ        // -Z
+)
+
+// CubeTexture 立方体贴图，由六个面的图像组成，按三维方向采样
+// 各面的朝向与 OpenGL 约定一致
+type CubeTexture struct {
+	Faces [6]*ImageTexture // 按 +X, -X, +Y, -Y, +Z, -Z 顺序排列的六个面
+}
+
+// NewCubeTexture 由按 +X, -X, +Y, -Y, +Z, -Z 顺序排列的六个图像创建立方体贴图
+func NewCubeTexture(faces [6]image.Image) *CubeTexture {
+	t := &CubeTexture{}
+	for i, im := range faces {
+		t.Faces[i] = NewImageTexture(im).(*ImageTexture)
+	}
+	return t
+}
+
+// LoadCubeTexture 从按 +X, -X, +Y, -Y, +Z, -Z 顺序排列的六个文件加载立方体贴图
+func LoadCubeTexture(paths ...string) (*CubeTexture, error) {
+	if len(paths) != 6 {
+		return nil, fmt.Errorf("cube texture requires 6 faces, got %d", len(paths))
+	}
+	var faces [6]image.Image
+	for i, path := range paths {
+		im, err := LoadImage(path)
+		if err != nil {
+			return nil, err
+		}
+		faces[i] = im
+	}
+	return NewCubeTexture(faces), nil
+}
+
+// LoadCubeTextureImage 从单个十字形或条带形图像文件加载立方体贴图
+func LoadCubeTextureImage(path string) (*CubeTexture, error) {
+	im, err := LoadImage(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewCubeTextureFromImage(im)
+}
+
+// NewCubeTextureFromImage 由单个图像创建立方体贴图，根据长宽比识别布局：
+// 4:3 横向十字、3:4 纵向十字、6:1 横向条带或 1:6 纵向条带
+// 十字布局以 +Z 为中心，条带按 +X, -X, +Y, -Y, +Z, -Z 顺序排列
+func NewCubeTextureFromImage(im image.Image) (*CubeTexture, error) {
+	b := im.Bounds()
+	w := b.Dx()
+	h := b.Dy()
+	type cell struct {
+		x, y    int
+		flipped bool
+	}
+	var n int
+	var cells [6]cell
+	switch {
+	case w*3 == h*4:
+		n = w / 4
+		cells = [6]cell{{2, 1, false}, {0, 1, false}, {1, 0, false}, {1, 2, false}, {1, 1, false}, {3, 1, false}}
+	case w*4 == h*3:
+		n = w / 3
+		cells = [6]cell{{2, 1, false}, {0, 1, false}, {1, 0, false}, {1, 2, false}, {1, 1, false}, {1, 3, true}}
+	case w == h*6:
+		n = h
+		cells = [6]cell{{0, 0, false}, {1, 0, false}, {2, 0, false}, {3, 0, false}, {4, 0, false}, {5, 0, false}}
+	case h == w*6:
+		n = w
+		cells = [6]cell{{0, 0, false}, {0, 1, false}, {0, 2, false}, {0, 3, false}, {0, 4, false}, {0, 5, false}}
+	default:
+		return nil, fmt.Errorf("unrecognized cube texture layout: %dx%d", w, h)
+	}
+	var faces [6]image.Image
+	for i, c := range cells {
+		x0 := b.Min.X + c.x*n
+		y0 := b.Min.Y + c.y*n
+		face := image.NewNRGBA(image.Rect(0, 0, n, n))
+		for y := 0; y < n; y++ {
+			for x := 0; x < n; x++ {
+				sx, sy := x, y
+				if c.flipped {
+					// 纵向十字中的 -Z 面旋转了 180 度
+					sx, sy = n-1-x, n-1-y
+				}
+				face.Set(x, y, im.At(x0+sx, y0+sy))
+			}
+		}
+		faces[i] = face
+	}
+	return NewCubeTexture(faces), nil
+}
+
+// cubeFaceCoords 返回方向 d 所在的面以及面内坐标 (s, t)，t 自图像顶部向下增加
+func cubeFaceCoords(d Vector) (int, float64, float64) {
+	a := d.Abs()
+	var face int
+	var ma, sc, tc float64
+	switch {
+	case a.X >= a.Y && a.X >= a.Z:
+		ma = a.X
+		if d.X > 0 {
+			face, sc, tc = CubePositiveX, -d.Z, -d.Y
+		} else {
+			face, sc, tc = CubeNegativeX, d.Z, -d.Y
+		}
+	case a.Y >= a.Z:
+		ma = a.Y
+		if d.Y > 0 {
+			face, sc, tc = CubePositiveY, d.X, d.Z
+		} else {
+			face, sc, tc = CubeNegativeY, d.X, -d.Z
+		}
+	default:
+		ma = a.Z
+		if d.Z > 0 {
+			face, sc, tc = CubePositiveZ, d.X, -d.Y
+		} else {
+			face, sc, tc = CubeNegativeZ, -d.X, -d.Y
+		}
+	}
+	if ma == 0 {
+		return CubePositiveZ, 0.5, 0.5
+	}
+	return face, (sc/ma + 1) / 2, (tc/ma + 1) / 2
+}
+
+// cubeFaceDirection 是 cubeFaceCoords 的逆运算，返回面内坐标 (s, t) 对应的方向
+func cubeFaceDirection(face int, s, t float64) Vector {
+	sc := s*2 - 1
+	tc := t*2 - 1
+	switch face {
+	case CubePositiveX:
+		return Vector{1, -tc, -sc}
+	case CubeNegativeX:
+		return Vector{-1, -tc, sc}
+	case CubePositiveY:
+		return Vector{sc, 1, tc}
+	case CubeNegativeY:
+		return Vector{sc, -1, -tc}
+	case CubePositiveZ:
+		return Vector{sc, -tc, 1}
+	default:
+		return Vector{-sc, -tc, -1}
+	}
+}
+
+// SampleDirection 对方向 d 进行最近邻采样
+func (t *CubeTexture) SampleDirection(d Vector) Color {
+	face, s, u := cubeFaceCoords(d)
+	f := t.Faces[face]
+	x := int(math.Floor(s * float64(f.Width)))
+	y := int(math.Floor(u * float64(f.Height)))
+	return t.texel(face, x, y)
+}
+
+// BilinearSampleDirection 使用双线性插值对方向 d 进行采样
+// 跨越面边界的纹素从相邻的面中读取，因此面之间没有接缝
+func (t *CubeTexture) BilinearSampleDirection(d Vector) Color {
+	face, s, u := cubeFaceCoords(d)
+	f := t.Faces[face]
+	x := s*float64(f.Width) - 0.5
+	y := u*float64(f.Height) - 0.5
+	fx := math.Floor(x)
+	fy := math.Floor(y)
+	x0 := int(fx)
+	y0 := int(fy)
+	x -= fx
+	y -= fy
+	c00 := t.texel(face, x0, y0)
+	c01 := t.texel(face, x0, y0+1)
+	c10 := t.texel(face, x0+1, y0)
+	c11 := t.texel(face, x0+1, y0+1)
+	c := Color{}
+	c = c.Add(c00.MulScalar((1 - x) * (1 - y)))
+	c = c.Add(c10.MulScalar(x * (1 - y)))
+	c = c.Add(c01.MulScalar((1 - x) * y))
+	c = c.Add(c11.MulScalar(x * y))
+	return c
+}
+
+// texel 返回面 face 中 (x, y) 处的纹素，超出该面的坐标投影到相邻的面
+func (t *CubeTexture) texel(face, x, y int) Color {
+	f := t.Faces[face]
+	if x >= 0 && x < f.Width && y >= 0 && y < f.Height {
+		return f.at(x, y)
+	}
+	s := (float64(x) + 0.5) / float64(f.Width)
+	u := (float64(y) + 0.5) / float64(f.Height)
+	face, s, u = cubeFaceCoords(cubeFaceDirection(face, s, u))
+	f = t.Faces[face]
+	x = ClampInt(int(s*float64(f.Width)), 0, f.Width-1)
+	y = ClampInt(int(u*float64(f.Height)), 0, f.Height-1)
+	return f.at(x, y)
+}
+
+// SkyboxShader 按顶点位置的方向对立方体贴图采样，用于绘制天空盒
+// 通常将一个以视点为中心的立方体或球体作为天空盒网格
+type SkyboxShader struct {
+	Matrix  Matrix
+	Texture DirectionTexture
+}
+
+// NewSkyboxShader 创建一个天空盒着色器
+func NewSkyboxShader(matrix Matrix, texture DirectionTexture) *SkyboxShader {
+	return &SkyboxShader{matrix, texture}
+}
+
+// Vertex 顶点着色器
+func (shader *SkyboxShader) Vertex(v Vertex) Vertex {
+	v.Output = shader.Matrix.MulPositionW(v.Position)
+	return v
+}
+
+// Fragment 片元着色器
+func (shader *SkyboxShader) Fragment(v Vertex) Color {
+	return shader.Texture.BilinearSampleDirection(v.Position)
+}
+
+// Reflection 返回一个混合环境反射的片元修饰器
+// eye 是视点位置，amount 是反射所占的比例
+func Reflection(eye Vector, environment DirectionTexture, amount float64) FragmentModifier {
+	return func(v Vertex, color Color) Color {
+		incident := v.Position.Sub(eye).Normalize()
+		reflected := environment.BilinearSampleDirection(incident.Reflect(v.Normal))
+		return color.Lerp(reflected, amount).Alpha(color.A)
+	}
+}
@@ -0,0 +1,77 @@
+package fauxgl
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+)
+
+// LinearDepth 返回每个像素的视空间深度（到相机平面的距离），按行从上到下排列
+// projection 是绘制时使用的投影矩阵，未被绘制的背景像素为 0
+func (dc *Context) LinearDepth(projection Matrix) []float32 {
+	positions, ok := dc.viewPositions(projection)
+	result := make([]float32, dc.Width*dc.Height)
+	for i, p := range positions {
+		if ok[i] {
+			result[i] = float32(-p.Z)
+		}
+	}
+	return result
+}
+
+// WorldPositions 返回每个像素的世界坐标，每个像素依次为 x, y, z 三个值，按行从上到下排列
+// matrix 是绘制时将世界坐标变换到裁剪坐标的矩阵，通常为投影矩阵与视图矩阵的乘积
+// 未被绘制的背景像素为 0
+func (dc *Context) WorldPositions(matrix Matrix) []float32 {
+	inverse := matrix.Inverse()
+	result := make([]float32, dc.Width*dc.Height*3)
+	parallelFor(dc.Height, func(y int) {
+		for x := 0; x < dc.Width; x++ {
+			i := y*dc.Width + x
+			z := dc.DepthBuffer[i]
+			if z == math.MaxFloat64 {
+				continue
+			}
+			p := dc.unproject(x, y, z, inverse)
+			result[i*3+0] = float32(p.X)
+			result[i*3+1] = float32(p.Y)
+			result[i*3+2] = float32(p.Z)
+		}
+	})
+	return result
+}
+
+// SavePFM 将浮点图像保存为 PFM 文件
+// data 按行从上到下排列，channels 为 1（灰度）或 3（RGB）
+func SavePFM(path string, width, height, channels int, data []float32) error {
+	var magic string
+	switch channels {
+	case 1:
+		magic = "Pf"
+	case 3:
+		magic = "PF"
+	default:
+		return fmt.Errorf("pfm supports 1 or 3 channels, got %d", channels)
+	}
+	if len(data) != width*height*channels {
+		return fmt.Errorf("pfm data has %d values, expected %d", len(data), width*height*channels)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	w := bufio.NewWriter(file)
+	// 负的比例因子表示小端字节序
+	fmt.Fprintf(w, "%s\n%d %d\n-1.0\n", magic, width, height)
+	// PFM 的行按从下到上的顺序存储
+	stride := width * channels
+	for y := height - 1; y >= 0; y-- {
+		if err := binary.Write(w, binary.LittleEndian, data[y*stride:(y+1)*stride]); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,123 @@
+package fauxgl
+
+import "math"
+
+// DepthOfField 景深后处理参数
+type DepthOfField struct {
+	FocalDistance float64 // 对焦平面到相机的视空间距离
+	Aperture      float64 // 无穷远处的弥散圆半径（像素），越大景深越浅
+	MaxRadius     float64 // 弥散圆半径的上限（像素），也是采样范围
+	Samples       int     // 每个像素的采样数
+}
+
+// NewDepthOfField 创建景深后处理
+func NewDepthOfField(focalDistance, aperture float64) *DepthOfField {
+	return &DepthOfField{focalDistance, aperture, aperture * 2, 64}
+}
+
+// CircleOfConfusion 返回视空间距离 distance 处的弥散圆半径（像素）
+func (d *DepthOfField) CircleOfConfusion(distance float64) float64 {
+	if distance <= 0 {
+		return d.MaxRadius
+	}
+	return math.Min(d.Aperture*math.Abs(1-d.FocalDistance/distance), d.MaxRadius)
+}
+
+// Apply 以后处理的方式对颜色缓冲区应用景深
+// projection 是绘制时使用的投影矩阵，深度缓冲区通过其近平面和远平面还原为视空间距离
+// 每个像素收集周围弥散圆覆盖到它的像素；位于更远处的像素只在当前像素自身也模糊时才参与，
+// 因此清晰的前景不会被模糊的背景侵蚀，清晰的物体也不会扩散到背景上
+// 颜色缓冲区被视为 sRGB 编码的，模糊在线性空间中以预乘透明度的颜色进行
+func (d *DepthOfField) Apply(dc *Context, projection Matrix) {
+	w, h := dc.Width, dc.Height
+	positions, ok := dc.viewPositions(projection)
+
+	distances := make([]float64, w*h)
+	cocs := make([]float64, w*h)
+	colors := make([]Color, w*h)
+	parallelFor(h, func(y int) {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			distances[i] = math.Inf(1)
+			if ok[i] {
+				distances[i] = -positions[i].Z
+			}
+			cocs[i] = d.CircleOfConfusion(distances[i])
+			p := dc.ColorBuffer.Pix[dc.ColorBuffer.PixOffset(x, y):]
+			a := float64(p[3]) / 255
+			colors[i] = Color{
+				srgbToLinearTable[p[0]] * a,
+				srgbToLinearTable[p[1]] * a,
+				srgbToLinearTable[p[2]] * a,
+				a,
+			}
+		}
+	})
+
+	// 黄金角螺旋上的采样点，均匀覆盖半径为 MaxRadius 的圆盘
+	type offset struct {
+		dx, dy   int
+		distance float64
+	}
+	var offsets []offset
+	seen := map[[2]int]bool{{0, 0}: true}
+	golden := math.Pi * (3 - math.Sqrt(5))
+	for i := 0; i < d.Samples; i++ {
+		r := d.MaxRadius * math.Sqrt((float64(i)+0.5)/float64(d.Samples))
+		a := float64(i) * golden
+		dx := int(math.Round(r * math.Cos(a)))
+		dy := int(math.Round(r * math.Sin(a)))
+		if seen[[2]int{dx, dy}] {
+			continue
+		}
+		seen[[2]int{dx, dy}] = true
+		offsets = append(offsets, offset{dx, dy, math.Hypot(float64(dx), float64(dy))})
+	}
+
+	result := make([]Color, w*h)
+	changed := make([]bool, w*h)
+	parallelFor(h, func(y int) {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			c := colors[i]
+			total := 1.0
+			coc := cocs[i]
+			for _, o := range offsets {
+				nx, ny := x+o.dx, y+o.dy
+				if nx < 0 || ny < 0 || nx >= w || ny >= h {
+					continue
+				}
+				j := ny*w + nx
+				radius := cocs[j]
+				if distances[j] > distances[i] {
+					// 更远处的像素不能扩散到比它更清晰的像素上
+					radius = math.Min(radius, coc)
+				}
+				// 弥散圆边缘的覆盖率平滑过渡
+				weight := Clamp(radius-o.distance+0.5, 0, 1)
+				if weight == 0 {
+					continue
+				}
+				c = c.Add(colors[j].MulScalar(weight))
+				total += weight
+			}
+			result[i] = c.DivScalar(total)
+			changed[i] = total > 1
+		}
+	})
+
+	parallelFor(h, func(y int) {
+		for x := 0; x < w; x++ {
+			// 未被模糊的像素保持不变，避免颜色空间往返转换的舍入误差
+			if !changed[y*w+x] {
+				continue
+			}
+			c := result[y*w+x]
+			a := Clamp(c.A, 0, 1)
+			if a > 0 {
+				c = c.DivScalar(c.A)
+			}
+			dc.ColorBuffer.SetNRGBA(x, y, c.SRGB().Alpha(a).NRGBA())
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package fauxgl
+
+import "math"
+
+// EdgeOperator 表示边缘检测算子
+type EdgeOperator int
+
+const (
+	_ EdgeOperator = iota
+	// EdgeSobel 3x3 Sobel 算子
+	EdgeSobel
+	// EdgeRoberts 2x2 Roberts 交叉算子，计算量更小但对噪声更敏感
+	EdgeRoberts
+)
+
+// EdgeOutline 图像空间的轮廓线后处理参数
+// 在深度、法线和对象编号的不连续处绘制轮廓线，可以检测到不同部件相交处的线条
+type EdgeOutline struct {
+	Operator        EdgeOperator // 边缘检测算子
+	Color           Color        // 线条颜色，透明度用于混合
+	Thickness       int          // 线宽（像素）
+	DepthThreshold  float64      // 相对深度梯度的阈值，0 表示不检测深度边缘
+	NormalThreshold float64      // 法线梯度的阈值，0 表示不检测法线边缘
+	ObjectIDs       bool         // 是否在对象编号不同处绘制线条
+}
+
+// NewEdgeOutline 创建图像空间的轮廓线后处理
+func NewEdgeOutline(color Color, thickness int) *EdgeOutline {
+	return &EdgeOutline{EdgeSobel, color, thickness, 0.1, 0.5, true}
+}
+
+// gradient 返回 (x, y) 处的梯度模长，f(dx, dy) 返回相邻像素的值
+// 结果已归一化，单位阶跃的梯度约为 1
+func (o *EdgeOutline) gradient(f func(dx, dy int) float64) float64 {
+	if o.Operator == EdgeRoberts {
+		gx := f(0, 0) - f(1, 1)
+		gy := f(1, 0) - f(0, 1)
+		return math.Sqrt(gx*gx + gy*gy)
+	}
+	gx := f(1, -1) + 2*f(1, 0) + f(1, 1) - f(-1, -1) - 2*f(-1, 0) - f(-1, 1)
+	gy := f(-1, 1) + 2*f(0, 1) + f(1, 1) - f(-1, -1) - 2*f(0, -1) - f(1, -1)
+	return math.Sqrt(gx*gx+gy*gy) / 4
+}
+
+// Edges 返回每个像素是否位于边缘上（未加粗）
+// projection 是绘制时使用的投影矩阵
+// normals 是视空间法线，为 nil 时由深度缓冲区重建
+func (o *EdgeOutline) Edges(dc *Context, projection Matrix, normals []Vector) []bool {
+	w, h := dc.Width, dc.Height
+	positions, ok := dc.viewPositions(projection)
+	if normals == nil && (o.DepthThreshold > 0 || o.NormalThreshold > 0) {
+		normals = dc.viewNormals(positions, ok)
+	}
+	// 没有使用对象编号绘制时对象缓冲区未分配
+	var objects []int32
+	if o.ObjectIDs {
+		objects = dc.ObjectBuffer
+	}
+	// index 返回相邻像素的索引，超出图像时取边缘像素
+	index := func(x, y int) int {
+		return ClampInt(y, 0, h-1)*w + ClampInt(x, 0, w-1)
+	}
+	// 第一遍：背景与物体之间以及对象编号不同处的边缘只标记边界一侧的像素
+	// （被绘制的像素或对象编号较大的像素），深度和法线边缘记录相对于阈值的梯度
+	scores := make([]float64, w*h)
+	parallelFor(h, func(y int) {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			if !ok[i] {
+				continue
+			}
+			edge := false
+			for _, d := range [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+				j := index(x+d[0], y+d[1])
+				if !ok[j] || (objects != nil && objects[i] > objects[j]) {
+					edge = true
+					break
+				}
+			}
+			if edge {
+				scores[i] = math.Inf(1)
+				continue
+			}
+			var score float64
+			if o.DepthThreshold > 0 {
+				// 深度梯度相对于深度本身，掠射角的平面梯度较大，因此按视线与法线的夹角放宽阈值
+				p := positions[i]
+				g := o.gradient(func(dx, dy int) float64 {
+					return positions[index(x+dx, y+dy)].Z
+				})
+				v := p.Negate().Normalize()
+				threshold := o.DepthThreshold / math.Max(math.Abs(normals[i].Dot(v)), 0.1)
+				score = math.Max(score, g/-p.Z/threshold)
+			}
+			if o.NormalThreshold > 0 {
+				gx := o.gradient(func(dx, dy int) float64 { return normals[index(x+dx, y+dy)].X })
+				gy := o.gradient(func(dx, dy int) float64 { return normals[index(x+dx, y+dy)].Y })
+				gz := o.gradient(func(dx, dy int) float64 { return normals[index(x+dx, y+dy)].Z })
+				score = math.Max(score, math.Sqrt(gx*gx+gy*gy+gz*gz)/o.NormalThreshold)
+			}
+			scores[i] = score
+		}
+	})
+	// 第二遍：梯度算子在不连续处两侧的响应相同，沿变化较大的水平或垂直方向只保留局部最大值，
+	// 使所有类型的边缘宽度都是一个像素
+	edges := make([]bool, w*h)
+	parallelFor(h, func(y int) {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			s := scores[i]
+			if s <= 1 {
+				continue
+			}
+			// at 返回相邻像素的值，超出图像时取边缘像素
+			at := func(x, y int) float64 {
+				return scores[index(x, y)]
+			}
+			if math.IsInf(s, 1) {
+				edges[i] = true
+				continue
+			}
+			l, r, u, d := at(x-1, y), at(x+1, y), at(x, y-1), at(x, y+1)
+			if math.Abs(l-r) >= math.Abs(u-d) {
+				edges[i] = s >= l && s > r
+			} else {
+				edges[i] = s >= u && s > d
+			}
+		}
+	})
+	return edges
+}
+
+// Draw 将边缘加粗到线宽后以线条颜色绘制到颜色缓冲区中
+func (o *EdgeOutline) Draw(dc *Context, edges []bool) {
+	w, h := dc.Width, dc.Height
+	r := float64(o.Thickness-1) / 2
+	ri := int(math.Ceil(r))
+	parallelFor(h, func(y int) {
+		for x := 0; x < w; x++ {
+			found := false
+			for dy := -ri; dy <= ri && !found; dy++ {
+				for dx := -ri; dx <= ri; dx++ {
+					nx, ny := x+dx, y+dy
+					if nx < 0 || ny < 0 || nx >= w || ny >= h {
+						continue
+					}
+					if float64(dx*dx+dy*dy) > r*r+r {
+						continue
+					}
+					if edges[ny*w+nx] {
+						found = true
+						break
+					}
+				}
+			}
+			if found {
+				dc.blendPixel(x, y, o.Color)
+			}
+		}
+	})
+}
+
+// Apply 以后处理的方式检测边缘并绘制轮廓线，法线由深度缓冲区重建
+// projection 是绘制时使用的投影矩阵
+func (o *EdgeOutline) Apply(dc *Context, projection Matrix) {
+	o.Draw(dc, o.Edges(dc, projection, nil))
+}
+
+// blendPixel 将颜色 c 按其透明度混合到颜色缓冲区的像素 (x, y) 上
+func (dc *Context) blendPixel(x, y int, c Color) {
+	if dc.SRGBOutput {
+		dc.writeLinear(x, y, c)
+		return
+	}
+	a := Clamp(c.A, 0, 1)
+	if a >= 1 {
+		dc.ColorBuffer.SetNRGBA(x, y, c.NRGBA())
+		return
+	}
+	d := colorFromNRGBA(dc.ColorBuffer.NRGBAAt(x, y))
+	c = c.MulScalar(a).Add(d.MulScalar(1 - a))
+	c.A = a + d.A*(1-a)
+	dc.ColorBuffer.SetNRGBA(x, y, c.NRGBA())
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"math"
+
+	. "github.com/fogleman/fauxgl"
+)
+
+const (
+	width  = 1600
+	height = 1200
+	scale  = 4 // optional supersampling
+	fovy   = 30
+)
+
+func main() {
+	scene := NewScene()
+
+	// base plate
+	base := NewMeshNode(NewCube(), NewMaterial(HexColor("#468966")))
+	base.Transform(Scale(V(4, 4, 0.2)))
+	scene.Add(base)
+
+	// a ring of posts sharing one material, each with its own object id
+	posts := NewNode()
+	posts.Material = NewMaterial(HexColor("#FFB03B"))
+	for i := 0; i < 8; i++ {
+		a := float64(i) / 8 * 2 * math.Pi
+		post := NewMeshNode(NewCylinder(10, true), nil)
+		post.ID = i + 1
+		post.Transform(Scale(V(0.3, 0.3, 1)).Translate(V(0, 0, 0.6)))
+		post.Transform(Translate(V(math.Cos(a)*1.4, math.Sin(a)*1.4, 0)))
+		posts.Add(post)
+	}
+	posts.Transform(Rotate(V(0, 0, 1), Radians(10)))
+	scene.Add(posts)
+
+	// a sphere resting on top of a central pedestal
+	pedestal := NewMeshNode(NewCube(), NewMaterial(HexColor("#8E2800")))
+	pedestal.Transform(Scale(V(0.6, 0.6, 0.4)).Translate(V(0, 0, 0.3)))
+	sphere := NewMeshNode(NewSphere(4), NewMaterial(HexColor("#FFF0A5")))
+	sphere.Transform(Scale(V(0.5, 0.5, 0.5)).Translate(V(0, 0, 1)))
+	scene.Add(pedestal, sphere)
+
+	aspect := float64(width) / float64(height)
+	camera := NewPerspectiveCamera(V(3, -4, 3), V(0, 0, 0), V(0, 0, 1), fovy, aspect)
+	camera.FitToBox(scene.BoundingBox())
+	camera.Orbit(Radians(20), 0)
+
+	context := NewContext(width*scale, height*scale)
+	context.ClearColorBufferWith(White)
+	context.DrawScene(scene, camera)
+
+	SavePNG("out.png", context.Resolve(scale, FilterMitchell))
+}
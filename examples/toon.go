@@ -0,0 +1,43 @@
+package main
+
+import . "github.com/fogleman/fauxgl"
+
+const (
+	width  = 1600
+	height = 1600
+	fovy   = 30
+	near   = 1
+	far    = 10
+)
+
+var (
+	eye    = V(3, 1, 0.75)
+	center = V(0, -0.1, 0)
+	up     = V(0, 1, 0)
+)
+
+func main() {
+	mesh, err := LoadSTL("examples/bowser.stl")
+	if err != nil {
+		panic(err)
+	}
+	mesh.BiUnitCube()
+	mesh.SmoothNormalsThreshold(Radians(30))
+
+	context := NewContext(width, height)
+	context.ClearColorBufferWith(White)
+
+	aspect := float64(width) / float64(height)
+	matrix := LookAt(eye, center, up).Perspective(fovy, aspect, near, far)
+	light := V(0.75, 1, 0.25).Normalize()
+
+	shader := NewToonShader(matrix, light, 3)
+	shader.ObjectColor = HexColor("#FFB03B")
+	context.Shader = shader
+	context.DrawMesh(mesh)
+
+	outline := NewOutline(Black, 4)
+	context.DrawOutline(mesh, matrix, eye, outline)
+
+	SavePNG("out.png", context.Image())
+}
@@ -0,0 +1,132 @@
+package fauxgl
+
+import "math"
+
+// FogMode 表示雾效模式
+type FogMode int
+
+const (
+	_ FogMode = iota
+	// FogLinear 表示线性雾
+	FogLinear
+	// FogExp 表示指数雾
+	FogExp
+	// FogExp2 表示指数平方雾
+	FogExp2
+)
+
+// Fog 距离雾参数，根据视空间深度将颜色与雾色混合
+type Fog struct {
+	Mode    FogMode // 雾效模式
+	Color   Color   // 雾的颜色
+	Start   float64 // 线性雾的起始距离
+	End     float64 // 线性雾的结束距离
+	Density float64 // 指数雾的密度
+}
+
+// NewLinearFog 创建一个线性雾
+func NewLinearFog(color Color, start, end float64) *Fog {
+	return &Fog{FogLinear, color, start, end, 0}
+}
+
+// NewExpFog 创建一个指数雾
+func NewExpFog(color Color, density float64) *Fog {
+	return &Fog{FogExp, color, 0, 0, density}
+}
+
+// NewExp2Fog 创建一个指数平方雾
+func NewExp2Fog(color Color, density float64) *Fog {
+	return &Fog{FogExp2, color, 0, 0, density}
+}
+
+// Factor 返回视空间深度 depth 处的雾量，0 表示无雾，1 表示完全被雾覆盖
+func (f *Fog) Factor(depth float64) float64 {
+	var visibility float64
+	switch f.Mode {
+	case FogExp:
+		visibility = math.Exp(-f.Density * depth)
+	case FogExp2:
+		d := f.Density * depth
+		visibility = math.Exp(-d * d)
+	default:
+		if f.End == f.Start {
+			if depth < f.Start {
+				return 0
+			}
+			return 1
+		}
+		visibility = (f.End - depth) / (f.End - f.Start)
+	}
+	return 1 - Clamp(visibility, 0, 1)
+}
+
+// Blend 按视空间深度 depth 将颜色 c 与雾色混合，保留 c 的透明度
+func (f *Fog) Blend(c Color, depth float64) Color {
+	return c.Lerp(f.Color, f.Factor(depth)).Alpha(c.A)
+}
+
+// Apply 以后处理的方式对颜色缓冲区应用雾效
+// projection 是绘制时使用的投影矩阵，用于将深度缓冲区还原为视空间深度
+// 未被绘制的背景像素保持不变；使用 SRGBOutput 时在线性空间中与雾色混合
+func (f *Fog) Apply(dc *Context, projection Matrix) {
+	inverse := projection.Inverse()
+	parallelFor(dc.Height, func(y int) {
+		for x := 0; x < dc.Width; x++ {
+			z := dc.DepthBuffer[y*dc.Width+x]
+			if z == math.MaxFloat64 {
+				continue
+			}
+			depth := -dc.unproject(x, y, z, inverse).Z
+			c := colorFromNRGBA(dc.ColorBuffer.NRGBAAt(x, y))
+			if dc.SRGBOutput {
+				c = f.Blend(c.Linear(), depth).SRGB()
+			} else {
+				c = f.Blend(c, depth)
+			}
+			dc.ColorBuffer.SetNRGBA(x, y, c.NRGBA())
+		}
+	})
+}
+
+// FogShader 为任意着色器添加距离雾
+type FogShader struct {
+	Shader Shader // 被包装的着色器
+	View   Matrix // 将顶点位置变换到视空间的矩阵，例如 LookAt 矩阵
+	Fog    *Fog   // 雾效参数
+}
+
+// NewFogShader 创建一个为 shader 添加距离雾的着色器
+func NewFogShader(shader Shader, view Matrix, fog *Fog) *FogShader {
+	return &FogShader{shader, view, fog}
+}
+
+// Vertex 顶点着色器
+func (shader *FogShader) Vertex(v Vertex) Vertex {
+	return shader.Shader.Vertex(v)
+}
+
+// Fragment 片元着色器
+func (shader *FogShader) Fragment(v Vertex) Color {
+	return shader.blend(v, shader.Shader.Fragment(v))
+}
+
+// Derivatives 被包装的着色器需要偏导数时返回 true
+func (shader *FogShader) Derivatives() bool {
+	ds, ok := shader.Shader.(DerivativeShader)
+	return ok && ds.Derivatives()
+}
+
+// FragmentDerivatives 调用被包装的着色器的 FragmentDerivatives，再混合雾色
+func (shader *FogShader) FragmentDerivatives(v, right, below Vertex) Color {
+	ds := shader.Shader.(DerivativeShader)
+	return shader.blend(v, ds.FragmentDerivatives(v, right, below))
+}
+
+// blend 按片元的视空间深度混合雾色
+func (shader *FogShader) blend(v Vertex, color Color) Color {
+	if color == Discard {
+		return color
+	}
+	depth := -shader.View.MulPosition(v.Position).Z
+	return shader.Fog.Blend(color, depth)
+}
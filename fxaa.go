@@ -0,0 +1,188 @@
+package fauxgl
+
+import "math"
+
+// FXAA 快速近似抗锯齿后处理参数
+// 在单个像素分辨率下检测亮度边缘并沿边缘方向混合，比超采样快得多，但会使细节略微模糊
+type FXAA struct {
+	Subpixel         float64 // 子像素混叠的去除程度，0 表示关闭，1 表示最柔和
+	EdgeThreshold    float64 // 相对局部最大亮度的边缘阈值，越小检测到的边缘越多
+	EdgeThresholdMin float64 // 绝对亮度的边缘阈值，低于该对比度的暗部区域不处理
+}
+
+// NewFXAA 创建快速近似抗锯齿，使用 FXAA 3.11 推荐的默认参数
+func NewFXAA() *FXAA {
+	return &FXAA{0.75, 0.166, 0.0833}
+}
+
+// fxaaSteps 沿边缘搜索端点时的步长（像素）
+var fxaaSteps = []float64{1, 1, 1, 1, 1, 1.5, 2, 2, 2, 2, 4, 8}
+
+// luma 返回颜色的感知亮度
+func luma(c Color) float64 {
+	return 0.299*c.R + 0.587*c.G + 0.114*c.B
+}
+
+// Apply 对颜色缓冲区进行抗锯齿
+func (f *FXAA) Apply(dc *Context) {
+	w, h := dc.Width, dc.Height
+
+	// 读取预乘透明度的颜色和亮度
+	colors := make([]Color, w*h)
+	lumas := make([]float64, w*h)
+	parallelFor(h, func(y int) {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			c := colorFromNRGBA(dc.ColorBuffer.NRGBAAt(x, y))
+			c = c.MulScalar(c.A).Alpha(c.A)
+			colors[i] = c
+			lumas[i] = luma(c)
+		}
+	})
+
+	at := func(x, y int) float64 {
+		return lumas[ClampInt(y, 0, h-1)*w+ClampInt(x, 0, w-1)]
+	}
+	// bilinear 以像素为单位的坐标 (x, y) 处双线性插值，像素中心位于整数坐标
+	bilinear := func(x, y float64) (float64, Color) {
+		x0 := math.Floor(x)
+		y0 := math.Floor(y)
+		fx := x - x0
+		fy := y - y0
+		var l float64
+		var c Color
+		for j := 0; j < 2; j++ {
+			for i := 0; i < 2; i++ {
+				wx := 1 - fx
+				if i == 1 {
+					wx = fx
+				}
+				wy := 1 - fy
+				if j == 1 {
+					wy = fy
+				}
+				weight := wx * wy
+				if weight == 0 {
+					continue
+				}
+				k := ClampInt(int(y0)+j, 0, h-1)*w + ClampInt(int(x0)+i, 0, w-1)
+				l += lumas[k] * weight
+				c = c.Add(colors[k].MulScalar(weight))
+			}
+		}
+		return l, c
+	}
+
+	result := make([]Color, w*h)
+	changed := make([]bool, w*h)
+	parallelFor(h, func(y int) {
+		for x := 0; x < w; x++ {
+			// 局部对比度检测
+			m := at(x, y)
+			n := at(x, y-1)
+			s := at(x, y+1)
+			e := at(x+1, y)
+			wl := at(x-1, y)
+			lo := math.Min(m, math.Min(math.Min(n, s), math.Min(e, wl)))
+			hi := math.Max(m, math.Max(math.Max(n, s), math.Max(e, wl)))
+			contrast := hi - lo
+			if contrast < math.Max(f.EdgeThresholdMin, hi*f.EdgeThreshold) {
+				continue
+			}
+			nw := at(x-1, y-1)
+			ne := at(x+1, y-1)
+			sw := at(x-1, y+1)
+			se := at(x+1, y+1)
+
+			// 子像素混叠
+			average := (2*(n+s+e+wl) + nw + ne + sw + se) / 12
+			sub := Clamp(math.Abs(average-m)/contrast, 0, 1)
+			sub = (-2*sub + 3) * sub * sub
+			sub = sub * sub * f.Subpixel
+
+			// 判断边缘方向
+			horizontal := math.Abs(nw-2*wl+sw)+2*math.Abs(n-2*m+s)+math.Abs(ne-2*e+se) >=
+				math.Abs(nw-2*n+ne)+2*math.Abs(wl-2*m+e)+math.Abs(sw-2*s+se)
+			l1, l2 := wl, e
+			if horizontal {
+				l1, l2 = n, s
+			}
+			g1 := l1 - m
+			g2 := l2 - m
+			step := 1.0
+			local := 0.5 * (l2 + m)
+			if math.Abs(g1) >= math.Abs(g2) {
+				step = -1
+				local = 0.5 * (l1 + m)
+			}
+			gradient := 0.25 * math.Max(math.Abs(g1), math.Abs(g2))
+
+			// 从两像素之间的边缘开始沿边缘方向搜索两端
+			px, py := float64(x), float64(y)
+			dx, dy := 0.0, 1.0
+			if horizontal {
+				py += step * 0.5
+				dx, dy = 1, 0
+			} else {
+				px += step * 0.5
+			}
+			var end1, end2 float64
+			var dist1, dist2 float64
+			done1, done2 := false, false
+			for _, d := range fxaaSteps {
+				if !done1 {
+					dist1 += d
+					l, _ := bilinear(px-dx*dist1, py-dy*dist1)
+					end1 = l - local
+					done1 = math.Abs(end1) >= gradient
+				}
+				if !done2 {
+					dist2 += d
+					l, _ := bilinear(px+dx*dist2, py+dy*dist2)
+					end2 = l - local
+					done2 = math.Abs(end2) >= gradient
+				}
+				if done1 && done2 {
+					break
+				}
+			}
+
+			// 根据到较近端点的距离估计边缘覆盖率
+			end := end2
+			distance := dist2
+			if dist1 < dist2 {
+				end = end1
+				distance = dist1
+			}
+			offset := 0.0
+			if (end < 0) != (m < local) {
+				offset = 0.5 - distance/(dist1+dist2)
+			}
+			offset = math.Max(offset, sub)
+
+			sx, sy := float64(x), float64(y)
+			if horizontal {
+				sy += offset * step
+			} else {
+				sx += offset * step
+			}
+			_, c := bilinear(sx, sy)
+			result[y*w+x] = c
+			changed[y*w+x] = true
+		}
+	})
+
+	parallelFor(h, func(y int) {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			if !changed[i] {
+				continue
+			}
+			c := result[i]
+			if c.A > 0 {
+				c = c.DivScalar(c.A).Alpha(c.A)
+			}
+			dc.ColorBuffer.SetNRGBA(x, y, c.NRGBA())
+		}
+	})
+}
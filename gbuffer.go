@@ -0,0 +1,159 @@
+package fauxgl
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// GBuffer 几何缓冲区，在绘制颜色的同一遍中记录每个像素最近表面的法线和纹理坐标
+// 与上下文的深度缓冲区和对象缓冲区一起，可以导出法线图、分割掩码和纹理坐标图
+type GBuffer struct {
+	Context *Context // 所属的上下文
+	View    Matrix   // 视图矩阵，用于计算视空间法线
+	Normals []Vector // 世界空间法线
+	Texture []Vector // 纹理坐标
+}
+
+// NewGBuffer 为上下文创建几何缓冲区并启用写入，同时分配上下文的对象缓冲区
+// view 是绘制时使用的视图矩阵，例如 LookAt 矩阵
+func NewGBuffer(dc *Context, view Matrix) *GBuffer {
+	n := dc.Width * dc.Height
+	g := &GBuffer{dc, view, make([]Vector, n), make([]Vector, n)}
+	dc.GBuffer = g
+	dc.objectBuffer()
+	return g
+}
+
+// write 记录像素 i 处片元的插值顶点数据
+func (g *GBuffer) write(i int, v Vertex) {
+	n := v.Normal
+	if l := n.Length(); l > 0 {
+		n = n.DivScalar(l)
+	}
+	g.Normals[i] = n
+	g.Texture[i] = v.Texture
+}
+
+// clear 清除几何缓冲区
+func (g *GBuffer) clear() {
+	for i := range g.Normals {
+		g.Normals[i] = Vector{}
+		g.Texture[i] = Vector{}
+	}
+}
+
+// covered 返回像素 i 是否被绘制
+func (g *GBuffer) covered(i int) bool {
+	return g.Context.DepthBuffer[i] != math.MaxFloat64
+}
+
+// encodeNormal 将单位法线编码为 16 位 RGB 颜色
+func encodeNormal(n Vector) color.NRGBA64 {
+	const d = 0xffff
+	return color.NRGBA64{
+		uint16(Clamp(n.X*0.5+0.5, 0, 1) * d),
+		uint16(Clamp(n.Y*0.5+0.5, 0, 1) * d),
+		uint16(Clamp(n.Z*0.5+0.5, 0, 1) * d),
+		d,
+	}
+}
+
+// normalImage 返回以 transform 变换后的法线编码为 RGB 的图像，背景透明
+func (g *GBuffer) normalImage(transform func(Vector) Vector) *image.NRGBA64 {
+	dc := g.Context
+	im := image.NewNRGBA64(image.Rect(0, 0, dc.Width, dc.Height))
+	for y := 0; y < dc.Height; y++ {
+		for x := 0; x < dc.Width; x++ {
+			i := y*dc.Width + x
+			if g.covered(i) {
+				im.SetNRGBA64(x, y, encodeNormal(transform(g.Normals[i])))
+			}
+		}
+	}
+	return im
+}
+
+// WorldNormalImage 返回世界空间法线图，分量从 [-1, 1] 映射到 [0, 1]，背景透明
+func (g *GBuffer) WorldNormalImage() *image.NRGBA64 {
+	return g.normalImage(func(n Vector) Vector {
+		return n
+	})
+}
+
+// ViewNormalImage 返回视空间法线图，分量从 [-1, 1] 映射到 [0, 1]，背景透明
+func (g *GBuffer) ViewNormalImage() *image.NRGBA64 {
+	return g.normalImage(func(n Vector) Vector {
+		return g.View.MulDirection(n).Normalize()
+	})
+}
+
+// ViewNormals 返回视空间法线，未被绘制的像素为零向量
+// 结果可以传给 SSAO.Compute 和 EdgeOutline.Edges 以代替由深度重建的法线
+func (g *GBuffer) ViewNormals() []Vector {
+	result := make([]Vector, len(g.Normals))
+	for i, n := range g.Normals {
+		if g.covered(i) {
+			result[i] = g.View.MulDirection(n).Normalize()
+		}
+	}
+	return result
+}
+
+// UVImage 返回纹理坐标图，红色和绿色通道分别为 u 和 v，背景透明
+// 超出 [0, 1] 的纹理坐标被截断
+func (g *GBuffer) UVImage() *image.NRGBA64 {
+	dc := g.Context
+	im := image.NewNRGBA64(image.Rect(0, 0, dc.Width, dc.Height))
+	for y := 0; y < dc.Height; y++ {
+		for x := 0; x < dc.Width; x++ {
+			i := y*dc.Width + x
+			if g.covered(i) {
+				t := g.Texture[i]
+				u := uint16(Clamp(t.X, 0, 1) * 0xffff)
+				v := uint16(Clamp(t.Y, 0, 1) * 0xffff)
+				im.SetNRGBA64(x, y, color.NRGBA64{u, v, 0, 0xffff})
+			}
+		}
+	}
+	return im
+}
+
+// MaskImage 返回对象编号为 id 的像素的掩码图像，属于该对象的像素为白色
+func (g *GBuffer) MaskImage(id int) *image.Gray {
+	dc := g.Context
+	im := image.NewGray(image.Rect(0, 0, dc.Width, dc.Height))
+	for i, o := range dc.ObjectBuffer {
+		if int(o) == id && g.covered(i) {
+			im.Pix[i] = 0xff
+		}
+	}
+	return im
+}
+
+// SegmentationImage 返回以对象编号为像素值的分割图像，背景为 0
+// 对象编号被截断到 16 位
+func (g *GBuffer) SegmentationImage() *image.Gray16 {
+	dc := g.Context
+	im := image.NewGray16(image.Rect(0, 0, dc.Width, dc.Height))
+	for y := 0; y < dc.Height; y++ {
+		for x := 0; x < dc.Width; x++ {
+			i := y*dc.Width + x
+			if g.covered(i) {
+				id := ClampInt(int(dc.ObjectBuffer[i]), 0, 0xffff)
+				im.SetGray16(x, y, color.Gray16{uint16(id)})
+			}
+		}
+	}
+	return im
+}
+
+// DrawMesh 使用对象编号 id 绘制网格，同时写入颜色缓冲区、深度缓冲区、对象缓冲区和几何缓冲区
+func (g *GBuffer) DrawMesh(mesh *Mesh, id int) RasterizeInfo {
+	dc := g.Context
+	previous := dc.ObjectID
+	dc.ObjectID = id
+	info := dc.DrawMesh(mesh)
+	dc.ObjectID = previous
+	return info
+}
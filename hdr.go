@@ -0,0 +1,201 @@
+package fauxgl
+
+import (
+	"image"
+	"math"
+)
+
+// ToneMapper 表示色调映射算子
+type ToneMapper int
+
+const (
+	_ ToneMapper = iota
+	// ToneMapClamp 直接截断到 [0, 1]
+	ToneMapClamp
+	// ToneMapReinhard Reinhard 算子 x / (1 + x)
+	ToneMapReinhard
+	// ToneMapACES ACES 电影曲线的近似拟合
+	ToneMapACES
+	// ToneMapFilmic Hable 的胶片曲线（Uncharted 2）
+	ToneMapFilmic
+)
+
+// hable Hable 胶片曲线
+func hable(x float64) float64 {
+	const a, b, c, d, e, f = 0.15, 0.5, 0.1, 0.2, 0.02, 0.3
+	return (x*(a*x+c*b)+d*e)/(x*(a*x+b)+d*f) - e/f
+}
+
+// Map 将线性的高动态范围分量 x 映射到 [0, 1]
+func (t ToneMapper) Map(x float64) float64 {
+	x = math.Max(x, 0)
+	switch t {
+	case ToneMapReinhard:
+		return x / (1 + x)
+	case ToneMapACES:
+		return Clamp((x*(2.51*x+0.03))/(x*(2.43*x+0.59)+0.14), 0, 1)
+	case ToneMapFilmic:
+		const white = 11.2
+		return Clamp(hable(2*x)/hable(white), 0, 1)
+	default:
+		return Clamp(x, 0, 1)
+	}
+}
+
+// HDRPipeline 高动态范围后处理流程
+// 依次进行曝光、泛光、色调映射、暗角和颜色分级，结果以 sRGB 编码写回颜色缓冲区
+type HDRPipeline struct {
+	Exposure       float64    // 曝光（档），颜色乘以 2^Exposure
+	ToneMapper     ToneMapper // 色调映射算子
+	BloomThreshold float64    // 泛光的亮度阈值，高于该亮度的部分产生泛光
+	BloomIntensity float64    // 泛光强度，0 表示关闭
+	BloomLevels    int        // 高斯金字塔的层数，层数越多泛光范围越大
+	Vignette       float64    // 暗角强度，0 表示关闭，1 表示角落完全变黑
+	LUT            *LUT       // 颜色分级查找表，输入和输出均为 sRGB 编码，nil 表示不使用
+}
+
+// NewHDRPipeline 创建使用 ACES 色调映射的高动态范围后处理流程
+func NewHDRPipeline() *HDRPipeline {
+	return &HDRPipeline{0, ToneMapACES, 1, 0.5, 5, 0, nil}
+}
+
+// hdrImage 高动态范围图像
+type hdrImage struct {
+	Width, Height int
+	Pix           []Color
+}
+
+// at 返回 (x, y) 处的颜色，超出图像时取边缘像素
+func (im *hdrImage) at(x, y int) Color {
+	x = ClampInt(x, 0, im.Width-1)
+	y = ClampInt(y, 0, im.Height-1)
+	return im.Pix[y*im.Width+x]
+}
+
+// bilinear 以像素为单位的坐标 (x, y) 处双线性插值，像素中心位于半整数坐标
+func (im *hdrImage) bilinear(x, y float64) Color {
+	x -= 0.5
+	y -= 0.5
+	x0 := math.Floor(x)
+	y0 := math.Floor(y)
+	fx := x - x0
+	fy := y - y0
+	ix, iy := int(x0), int(y0)
+	c0 := im.at(ix, iy).Lerp(im.at(ix+1, iy), fx)
+	c1 := im.at(ix, iy+1).Lerp(im.at(ix+1, iy+1), fx)
+	return c0.Lerp(c1, fy)
+}
+
+// gaussian5 5 抽头二项式高斯核
+var gaussian5 = [5]float64{1.0 / 16, 4.0 / 16, 6.0 / 16, 4.0 / 16, 1.0 / 16}
+
+// blurDown 高斯模糊后降采样为一半尺寸
+func (im *hdrImage) blurDown() *hdrImage {
+	w := (im.Width + 1) / 2
+	h := (im.Height + 1) / 2
+	// 水平方向模糊并降采样
+	tmp := &hdrImage{w, im.Height, make([]Color, w*im.Height)}
+	parallelFor(im.Height, func(y int) {
+		for x := 0; x < w; x++ {
+			var c Color
+			for k, g := range gaussian5 {
+				c = c.Add(im.at(x*2+k-2, y).MulScalar(g))
+			}
+			tmp.Pix[y*w+x] = c
+		}
+	})
+	// 垂直方向模糊并降采样
+	dst := &hdrImage{w, h, make([]Color, w*h)}
+	parallelFor(h, func(y int) {
+		for x := 0; x < w; x++ {
+			var c Color
+			for k, g := range gaussian5 {
+				c = c.Add(tmp.at(x, y*2+k-2).MulScalar(g))
+			}
+			dst.Pix[y*w+x] = c
+		}
+	})
+	return dst
+}
+
+// bloom 返回图像中高亮部分经高斯金字塔模糊后的泛光
+func (p *HDRPipeline) bloom(src *hdrImage) *hdrImage {
+	// 提取高于阈值的部分，按亮度缩放以保持色调
+	bright := &hdrImage{src.Width, src.Height, make([]Color, len(src.Pix))}
+	for i, c := range src.Pix {
+		l := luma(c)
+		if l > p.BloomThreshold {
+			bright.Pix[i] = c.MulScalar((l - p.BloomThreshold) / l).Alpha(0)
+		}
+	}
+	// 逐层降采样，再将各层放大并累加
+	levels := []*hdrImage{bright.blurDown()}
+	for i := 1; i < p.BloomLevels; i++ {
+		last := levels[len(levels)-1]
+		if last.Width < 2 || last.Height < 2 {
+			break
+		}
+		levels = append(levels, last.blurDown())
+	}
+	result := &hdrImage{src.Width, src.Height, make([]Color, len(src.Pix))}
+	parallelFor(src.Height, func(y int) {
+		for x := 0; x < src.Width; x++ {
+			var c Color
+			for _, level := range levels {
+				sx := (float64(x) + 0.5) * float64(level.Width) / float64(src.Width)
+				sy := (float64(y) + 0.5) * float64(level.Height) / float64(src.Height)
+				c = c.Add(level.bilinear(sx, sy))
+			}
+			result.Pix[y*src.Width+x] = c.DivScalar(float64(len(levels)))
+		}
+	})
+	return result
+}
+
+// Process 处理高动态范围的线性颜色 pix（宽 w 高 h），返回 sRGB 编码的图像
+func (p *HDRPipeline) Process(pix []Color, w, h int) *image.NRGBA {
+	exposure := math.Exp2(p.Exposure)
+	src := &hdrImage{w, h, make([]Color, len(pix))}
+	for i, c := range pix {
+		src.Pix[i] = c.MulScalar(exposure).Alpha(c.A)
+	}
+	var bloom *hdrImage
+	if p.BloomIntensity > 0 && p.BloomLevels > 0 {
+		bloom = p.bloom(src)
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	parallelFor(h, func(y int) {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			c := src.Pix[i]
+			a := Clamp(c.A, 0, 1)
+			if bloom != nil {
+				c = c.Add(bloom.Pix[i].MulScalar(p.BloomIntensity))
+				// 泛光可以照亮透明的背景
+				a = math.Max(a, Clamp(luma(bloom.Pix[i])*p.BloomIntensity, 0, 1))
+			}
+			c = Color{p.ToneMapper.Map(c.R), p.ToneMapper.Map(c.G), p.ToneMapper.Map(c.B), a}
+			if p.Vignette > 0 {
+				dx := (float64(x)+0.5)/float64(w)*2 - 1
+				dy := (float64(y)+0.5)/float64(h)*2 - 1
+				d := math.Sqrt(dx*dx+dy*dy) / math.Sqrt2
+				c = c.MulScalar(1 - p.Vignette*smoothstep(0.4, 1, d)).Alpha(a)
+			}
+			c = c.SRGB()
+			if p.LUT != nil {
+				c = p.LUT.Apply(c)
+			}
+			dst.SetNRGBA(x, y, c.NRGBA())
+		}
+	})
+	return dst
+}
+
+// Apply 处理上下文的高动态范围缓冲区并写回颜色缓冲区
+// 绘制前应设置 dc.HDR 为 true 并清除颜色缓冲区；使用 TransparencyWeighted 时应先调用 ResolveTransparency
+func (p *HDRPipeline) Apply(dc *Context) {
+	im := p.Process(dc.hdrBuffer(), dc.Width, dc.Height)
+	for y := 0; y < dc.Height; y++ {
+		copy(dc.ColorBuffer.Pix[dc.ColorBuffer.PixOffset(0, y):], im.Pix[im.PixOffset(0, y):im.PixOffset(dc.Width, y)])
+	}
+}
@@ -0,0 +1,129 @@
+package fauxgl
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LUT 三维颜色查找表，用于颜色分级
+type LUT struct {
+	Size      int     // 每个维度的格点数
+	DomainMin Vector  // 输入颜色的最小值
+	DomainMax Vector  // 输入颜色的最大值
+	Table     []Color // 格点颜色，红色分量变化最快
+}
+
+// NewIdentityLUT 创建不改变颜色的查找表
+func NewIdentityLUT(size int) *LUT {
+	table := make([]Color, 0, size*size*size)
+	s := float64(size - 1)
+	for b := 0; b < size; b++ {
+		for g := 0; g < size; g++ {
+			for r := 0; r < size; r++ {
+				table = append(table, Color{float64(r) / s, float64(g) / s, float64(b) / s, 1})
+			}
+		}
+	}
+	return &LUT{size, Vector{0, 0, 0}, Vector{1, 1, 1}, table}
+}
+
+// LoadCubeLUT 从 .cube 文件中加载三维颜色查找表
+func LoadCubeLUT(path string) (*LUT, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	lut := &LUT{DomainMin: Vector{0, 0, 0}, DomainMax: Vector{1, 1, 1}}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		switch fields[0] {
+		case "TITLE":
+			// 忽略标题
+		case "LUT_1D_SIZE":
+			return nil, fmt.Errorf("%s: 1D LUTs are not supported", path)
+		case "LUT_3D_SIZE":
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("%s: missing LUT_3D_SIZE value", path)
+			}
+			size, err := strconv.Atoi(fields[1])
+			if err != nil || size < 2 {
+				return nil, fmt.Errorf("%s: invalid LUT_3D_SIZE %q", path, fields[1])
+			}
+			lut.Size = size
+			lut.Table = make([]Color, 0, size*size*size)
+		case "DOMAIN_MIN", "DOMAIN_MAX":
+			f := ParseFloats(fields[1:])
+			if len(f) != 3 {
+				return nil, fmt.Errorf("%s: invalid %s", path, fields[0])
+			}
+			if fields[0] == "DOMAIN_MIN" {
+				lut.DomainMin = Vector{f[0], f[1], f[2]}
+			} else {
+				lut.DomainMax = Vector{f[0], f[1], f[2]}
+			}
+		case "LUT_3D_INPUT_RANGE":
+			f := ParseFloats(fields[1:])
+			if len(f) != 2 {
+				return nil, fmt.Errorf("%s: invalid %s", path, fields[0])
+			}
+			lut.DomainMin = Vector{f[0], f[0], f[0]}
+			lut.DomainMax = Vector{f[1], f[1], f[1]}
+		default:
+			if len(fields) != 3 {
+				// 未知关键字
+				continue
+			}
+			if lut.Size == 0 {
+				return nil, fmt.Errorf("%s: table data before LUT_3D_SIZE", path)
+			}
+			f := ParseFloats(fields)
+			lut.Table = append(lut.Table, Color{f[0], f[1], f[2], 1})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if lut.Size == 0 || len(lut.Table) != lut.Size*lut.Size*lut.Size {
+		return nil, fmt.Errorf("%s: expected %d table entries, got %d", path, lut.Size*lut.Size*lut.Size, len(lut.Table))
+	}
+	return lut, nil
+}
+
+// at 返回格点 (r, g, b) 处的颜色
+func (l *LUT) at(r, g, b int) Color {
+	return l.Table[(b*l.Size+g)*l.Size+r]
+}
+
+// Apply 使用三线性插值查找颜色 c 的映射结果，透明度保持不变
+func (l *LUT) Apply(c Color) Color {
+	n := l.Size - 1
+	scale := l.DomainMax.Sub(l.DomainMin)
+	// coord 将分量 x 映射到格点坐标，返回下方格点和插值系数
+	coord := func(x, lo, d float64) (int, float64) {
+		t := 0.0
+		if d != 0 {
+			t = Clamp((x-lo)/d, 0, 1) * float64(n)
+		}
+		i := ClampInt(int(t), 0, n-1)
+		return i, t - float64(i)
+	}
+	r, fr := coord(c.R, l.DomainMin.X, scale.X)
+	g, fg := coord(c.G, l.DomainMin.Y, scale.Y)
+	b, fb := coord(c.B, l.DomainMin.Z, scale.Z)
+	c00 := l.at(r, g, b).Lerp(l.at(r+1, g, b), fr)
+	c10 := l.at(r, g+1, b).Lerp(l.at(r+1, g+1, b), fr)
+	c01 := l.at(r, g, b+1).Lerp(l.at(r+1, g, b+1), fr)
+	c11 := l.at(r, g+1, b+1).Lerp(l.at(r+1, g+1, b+1), fr)
+	c0 := c00.Lerp(c10, fg)
+	c1 := c01.Lerp(c11, fg)
+	return c0.Lerp(c1, fb).Alpha(c.A)
+}
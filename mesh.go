@@ -67,6 +67,22 @@ func (m *Mesh) SetColor(c Color) {
 	}
 }
 
+// ScalarRange 返回网格顶点标量的最小值和最大值，没有三角形时返回 0, 0
+func (m *Mesh) ScalarRange() (float64, float64) {
+	if len(m.Triangles) == 0 {
+		return 0, 0
+	}
+	lo := math.MaxFloat64
+	hi := -math.MaxFloat64
+	for _, t := range m.Triangles {
+		for _, v := range []Vertex{t.V1, t.V2, t.V3} {
+			lo = math.Min(lo, v.Scalar)
+			hi = math.Max(hi, v.Scalar)
+		}
+	}
+	return lo, hi
+}
+
 // Volume 计算网格体积
 func (m *Mesh) Volume() float64 {
 	var v float64
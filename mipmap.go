@@ -0,0 +1,130 @@
+package fauxgl
+
+import (
+	"image"
+	"math"
+)
+
+// TrilinearSampler 是支持 mipmap 三线性采样的纹理
+type TrilinearSampler interface {
+	// HasMipmaps 返回是否已生成 mipmap 链
+	HasMipmaps() bool
+	// TrilinearSample 根据纹理坐标的屏幕空间偏导数 dx, dy 进行三线性采样
+	TrilinearSample(u, v float64, dx, dy Vector) Color
+}
+
+// hasMipmaps 判断纹理是否支持三线性采样
+func hasMipmaps(texture Texture) bool {
+	if t, ok := texture.(TrilinearSampler); ok {
+		return t.HasMipmaps()
+	}
+	return false
+}
+
+// trilinearSample 使用相邻像素处的插值顶点计算偏导数并对纹理进行三线性采样
+func trilinearSample(texture Texture, v, right, below Vertex) Color {
+	u := v.Texture.X
+	w := v.Texture.Y
+	if t, ok := texture.(TrilinearSampler); ok {
+		dx := right.Texture.Sub(v.Texture)
+		dy := below.Texture.Sub(v.Texture)
+		return t.TrilinearSample(u, w, dx, dy)
+	}
+	return texture.BilinearSample(u, w)
+}
+
+// NewMipmapTexture 创建带有 mipmap 链的纹理对象
+func NewMipmapTexture(im image.Image) Texture {
+	t := NewImageTexture(im).(*ImageTexture)
+	t.GenerateMipmaps()
+	return t
+}
+
+// LoadMipmapTexture 加载纹理并生成 mipmap 链
+func LoadMipmapTexture(path string) (Texture, error) {
+	im, err := LoadImage(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewMipmapTexture(im), nil
+}
+
+// GenerateMipmaps 生成 mipmap 链，每一级使用 2x2 盒式滤波将长宽减半，直到 1x1
+func (t *ImageTexture) GenerateMipmaps() {
+	t.Mipmaps = nil
+	level := t
+	for level.Width > 1 || level.Height > 1 {
+		level = level.downsample()
+		t.Mipmaps = append(t.Mipmaps, level)
+	}
+}
+
+// downsample 返回长宽减半的纹理，mipmap 级别只包含打包的纹素数据
+func (t *ImageTexture) downsample() *ImageTexture {
+	w := (t.Width + 1) / 2
+	h := (t.Height + 1) / 2
+	pix := make([]float32, w*h*4)
+	i := 0
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			x0 := x * 2
+			y0 := y * 2
+			x1 := ClampInt(x0+1, 0, t.Width-1)
+			y1 := ClampInt(y0+1, 0, t.Height-1)
+			c := t.at(x0, y0)
+			c = c.Add(t.at(x1, y0))
+			c = c.Add(t.at(x0, y1))
+			c = c.Add(t.at(x1, y1))
+			c = c.MulScalar(0.25)
+			pix[i+0] = float32(c.R)
+			pix[i+1] = float32(c.G)
+			pix[i+2] = float32(c.B)
+			pix[i+3] = float32(c.A)
+			i += 4
+		}
+	}
+	return &ImageTexture{w, h, nil, nil, t.WrapU, t.WrapV, t.BorderColor, t.SRGB, pix}
+}
+
+// HasMipmaps 返回是否已生成 mipmap 链
+func (t *ImageTexture) HasMipmaps() bool {
+	return len(t.Mipmaps) > 0
+}
+
+// Lod 根据纹理坐标的屏幕空间偏导数 dx, dy 计算细节层级
+func (t *ImageTexture) Lod(dx, dy Vector) float64 {
+	w := float64(t.Width)
+	h := float64(t.Height)
+	px := math.Hypot(dx.X*w, dx.Y*h)
+	py := math.Hypot(dy.X*w, dy.Y*h)
+	rho := math.Max(px, py)
+	if rho <= 0 {
+		return 0
+	}
+	return math.Log2(rho)
+}
+
+// TrilinearSample 在相邻两级 mipmap 的双线性采样结果之间进行线性插值
+// dx, dy 是纹理坐标在屏幕 x 和 y 方向上的偏导数
+func (t *ImageTexture) TrilinearSample(u, v float64, dx, dy Vector) Color {
+	lod := t.Lod(dx, dy)
+	if lod <= 0 || len(t.Mipmaps) == 0 {
+		return t.BilinearSample(u, v)
+	}
+	lod = math.Min(lod, float64(len(t.Mipmaps)))
+	i := int(lod)
+	f := lod - float64(i)
+	c := t.bilinear(t.mipmap(i), u, v)
+	if f > 0 && i < len(t.Mipmaps) {
+		c = c.Lerp(t.bilinear(t.mipmap(i+1), u, v), f)
+	}
+	return c
+}
+
+// mipmap 返回第 i 级 mipmap，第 0 级为纹理本身
+func (t *ImageTexture) mipmap(i int) *ImageTexture {
+	if i == 0 {
+		return t
+	}
+	return t.Mipmaps[i-1]
+}
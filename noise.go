@@ -0,0 +1,176 @@
+package fauxgl
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Noise 带有种子的三维梯度噪声（Perlin 噪声）
+type Noise struct {
+	perm [512]uint8 // 排列表
+}
+
+// NewNoise 使用种子 seed 创建噪声
+func NewNoise(seed int64) *Noise {
+	n := &Noise{}
+	p := rand.New(rand.NewSource(seed)).Perm(256)
+	for i := range n.perm {
+		n.perm[i] = uint8(p[i&255])
+	}
+	return n
+}
+
+// fade 缓和曲线 6t^5 - 15t^4 + 10t^3
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// grad 返回哈希值 h 对应的梯度与 (x, y, z) 的点积
+func grad(h uint8, x, y, z float64) float64 {
+	switch h & 15 {
+	case 0, 12:
+		return x + y
+	case 1, 14:
+		return -x + y
+	case 2:
+		return x - y
+	case 3:
+		return -x - y
+	case 4:
+		return x + z
+	case 5:
+		return -x + z
+	case 6:
+		return x - z
+	case 7:
+		return -x - z
+	case 8:
+		return y + z
+	case 9, 13:
+		return -y + z
+	case 10:
+		return y - z
+	default:
+		return -y - z
+	}
+}
+
+// lerp 线性插值
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+// Noise3 返回 (x, y, z) 处的噪声值，大致位于 [-1, 1] 范围内
+func (n *Noise) Noise3(x, y, z float64) float64 {
+	fx := math.Floor(x)
+	fy := math.Floor(y)
+	fz := math.Floor(z)
+	X := int(fx) & 255
+	Y := int(fy) & 255
+	Z := int(fz) & 255
+	x -= fx
+	y -= fy
+	z -= fz
+	u := fade(x)
+	v := fade(y)
+	w := fade(z)
+	p := &n.perm
+	A := int(p[X]) + Y
+	AA := int(p[A]) + Z
+	AB := int(p[A+1]) + Z
+	B := int(p[X+1]) + Y
+	BA := int(p[B]) + Z
+	BB := int(p[B+1]) + Z
+	return lerp(w,
+		lerp(v,
+			lerp(u, grad(p[AA], x, y, z), grad(p[BA], x-1, y, z)),
+			lerp(u, grad(p[AB], x, y-1, z), grad(p[BB], x-1, y-1, z))),
+		lerp(v,
+			lerp(u, grad(p[AA+1], x, y, z-1), grad(p[BA+1], x-1, y, z-1)),
+			lerp(u, grad(p[AB+1], x, y-1, z-1), grad(p[BB+1], x-1, y-1, z-1))))
+}
+
+// Fractal 返回 octaves 层分形布朗运动噪声，大致位于 [-1, 1] 范围内
+func (n *Noise) Fractal(x, y, z float64, octaves int) float64 {
+	var sum, total float64
+	amplitude := 1.0
+	for i := 0; i < octaves; i++ {
+		sum += n.Noise3(x, y, z) * amplitude
+		total += amplitude
+		amplitude /= 2
+		x, y, z = x*2, y*2, z*2
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// Turbulence 返回 octaves 层噪声绝对值之和，位于 [0, 1] 范围内
+func (n *Noise) Turbulence(x, y, z float64, octaves int) float64 {
+	var sum, total float64
+	amplitude := 1.0
+	for i := 0; i < octaves; i++ {
+		sum += math.Abs(n.Noise3(x, y, z)) * amplitude
+		total += amplitude
+		amplitude /= 2
+		x, y, z = x*2, y*2, z*2
+	}
+	if total == 0 {
+		return 0
+	}
+	return Clamp(sum/total, 0, 1)
+}
+
+// WorleyNoise 带有种子的三维细胞噪声（Worley 噪声）
+type WorleyNoise struct {
+	Seed int64 // 种子
+}
+
+// NewWorleyNoise 使用种子 seed 创建细胞噪声
+func NewWorleyNoise(seed int64) *WorleyNoise {
+	return &WorleyNoise{seed}
+}
+
+// hashCell 返回种子与整数格点坐标的哈希值
+func hashCell(seed int64, x, y, z int) uint64 {
+	h := uint64(seed) * 0x9e3779b97f4a7c15
+	h ^= uint64(x) * 0xbf58476d1ce4e5b9
+	h ^= uint64(y) * 0x94d049bb133111eb
+	h ^= uint64(z) * 0x2545f4914f6cdd1d
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// Distance 返回 (x, y, z) 到最近和次近特征点的距离
+func (w *WorleyNoise) Distance(x, y, z float64) (float64, float64) {
+	const m = 1 << 21
+	cx := int(math.Floor(x))
+	cy := int(math.Floor(y))
+	cz := int(math.Floor(z))
+	f1 := math.MaxFloat64
+	f2 := math.MaxFloat64
+	for i := cx - 1; i <= cx+1; i++ {
+		for j := cy - 1; j <= cy+1; j++ {
+			for k := cz - 1; k <= cz+1; k++ {
+				// 每个格子中有一个特征点，由哈希值的三组 21 位确定
+				h := hashCell(w.Seed, i, j, k)
+				px := float64(i) + float64(h&(m-1))/m
+				py := float64(j) + float64((h>>21)&(m-1))/m
+				pz := float64(k) + float64((h>>42)&(m-1))/m
+				dx, dy, dz := px-x, py-y, pz-z
+				d := dx*dx + dy*dy + dz*dz
+				if d < f1 {
+					f1, f2 = d, f1
+				} else if d < f2 {
+					f2 = d
+				}
+			}
+		}
+	}
+	return math.Sqrt(f1), math.Sqrt(f2)
+}
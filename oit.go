@@ -0,0 +1,95 @@
+package fauxgl
+
+import "math"
+
+// Transparency 表示透明片元的混合模式
+type Transparency int
+
+const (
+	_ Transparency = iota
+	// TransparencyOrdered 表示按绘制顺序混合，结果依赖于三角形的处理顺序
+	TransparencyOrdered
+	// TransparencyWeighted 表示加权混合的顺序无关透明（Weighted Blended OIT）
+	// 透明片元被累积到单独的缓冲区中，需要调用 ResolveTransparency 合成到颜色缓冲区
+	TransparencyWeighted
+)
+
+// oitScale 累积缓冲区的定点数比例
+// 使用整数累加使结果与累加顺序无关，因此多线程绘制的结果是确定的
+const oitScale = 1 << 20
+
+// oitSample 单个像素的透明累积值
+type oitSample struct {
+	R, G, B, A int64 // 加权的预乘颜色之和
+	LogT       int64 // 透射率的对数之和
+	Count      int32 // 累积的片元数
+}
+
+// oitWeight 返回屏幕深度 z 处透明度为 a 的片元的权重，越近的片元权重越大
+func oitWeight(z, a float64) float64 {
+	d := 1 - Clamp(z, 0, 1)
+	return a * math.Max(1e-2, 3e3*d*d*d)
+}
+
+// oitBuffer 返回透明累积缓冲区，首次使用时分配
+func (dc *Context) oitBuffer() []oitSample {
+	dc.oitOnce.Do(func() {
+		dc.oit = make([]oitSample, dc.Width*dc.Height)
+	})
+	return dc.oit
+}
+
+// accumulateTransparent 将透明片元累积到像素 i，调用者需要持有该像素的锁
+// 使用 SRGBOutput 时着色器输出的已经是线性颜色
+func (dc *Context) accumulateTransparent(i int, z float64, c Color) {
+	a := Clamp(c.A, 0, 1)
+	w := oitWeight(z, a)
+	s := &dc.oitBuffer()[i]
+	s.R += int64(c.R * a * w * oitScale)
+	s.G += int64(c.G * a * w * oitScale)
+	s.B += int64(c.B * a * w * oitScale)
+	s.A += int64(a * w * oitScale)
+	s.LogT += int64(math.Max(math.Log(1-a), -64) * oitScale)
+	s.Count++
+}
+
+// ResolveTransparency 将累积的透明片元合成到颜色缓冲区并清空累积缓冲区
+// 使用 TransparencyWeighted 时应先绘制不透明物体，再绘制透明物体，最后调用此方法
+// HDR 模式下合成到高动态范围缓冲区，应在 HDRPipeline.Apply 之前调用
+func (dc *Context) ResolveTransparency() {
+	if dc.oit == nil {
+		return
+	}
+	for y := 0; y < dc.Height; y++ {
+		for x := 0; x < dc.Width; x++ {
+			i := y*dc.Width + x
+			s := dc.oit[i]
+			if s.Count == 0 {
+				continue
+			}
+			dc.oit[i] = oitSample{}
+			revealage := math.Exp(float64(s.LogT) / oitScale)
+			a := math.Max(float64(s.A), 1)
+			c := Color{float64(s.R) / a, float64(s.G) / a, float64(s.B) / a, 1}
+			if dc.HDR {
+				// 高动态范围模式下合成到线性的高动态范围缓冲区
+				buffer := dc.hdrBuffer()
+				d := buffer[i]
+				c = c.MulScalar(1 - revealage).Add(d.MulScalar(revealage))
+				c.A = 1 - revealage + d.A*revealage
+				buffer[i] = c
+				continue
+			}
+			d := colorFromNRGBA(dc.ColorBuffer.NRGBAAt(x, y))
+			if dc.SRGBOutput {
+				d = d.Linear()
+			}
+			c = c.MulScalar(1 - revealage).Add(d.MulScalar(revealage))
+			c.A = 1 - revealage + d.A*revealage
+			if dc.SRGBOutput {
+				c = c.SRGB()
+			}
+			dc.ColorBuffer.SetNRGBA(x, y, c.NRGBA())
+		}
+	}
+}
@@ -3,6 +3,7 @@ package fauxgl
 import (
 	"bufio"
 	"encoding/binary"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -76,6 +77,12 @@ type plyElement struct {
 
 // LoadPLY 从PLY文件中加载网格
 func LoadPLY(path string) (*Mesh, error) {
+	return LoadPLYScalar(path, "")
+}
+
+// LoadPLYScalar 从PLY文件中加载网格，并将名为 scalar 的顶点属性读取到 Vertex.Scalar
+// 例如 "quality"、"intensity" 或自定义的仿真结果属性；顶点元素中没有该属性时返回错误
+func LoadPLYScalar(path, scalar string) (*Mesh, error) {
 	// 打开文件
 	file, err := os.Open(path)
 	if err != nil {
@@ -133,22 +140,42 @@ func LoadPLY(path string) (*Mesh, error) {
 		}
 	}
 
+	// 指定的标量属性必须存在于顶点元素中
+	if scalar != "" && !plyHasProperty(elements, "vertex", scalar) {
+		return nil, fmt.Errorf("%s: vertex property %q not found", path, scalar)
+	}
+
 	file.Seek(int64(bytes), 0)
 
 	switch format {
 	case plyBinaryBigEndian:
-		return loadPlyBinary(file, elements, binary.BigEndian)
+		return loadPlyBinary(file, elements, scalar, binary.BigEndian)
 	case plyBinaryLittleEndian:
-		return loadPlyBinary(file, elements, binary.LittleEndian)
+		return loadPlyBinary(file, elements, scalar, binary.LittleEndian)
 	default:
-		return loadPlyAscii(file, elements)
+		return loadPlyAscii(file, elements, scalar)
+	}
+}
+
+// plyHasProperty 返回名为 elementName 的元素是否包含名为 name 的属性
+func plyHasProperty(elements []plyElement, elementName, name string) bool {
+	for _, element := range elements {
+		if element.name != elementName {
+			continue
+		}
+		for _, property := range element.properties {
+			if property.name == name {
+				return true
+			}
+		}
 	}
+	return false
 }
 
 // 从PLY文件中加载网格（ASCII格式）
-func loadPlyAscii(file *os.File, elements []plyElement) (*Mesh, error) {
+func loadPlyAscii(file *os.File, elements []plyElement, scalar string) (*Mesh, error) {
 	scanner := bufio.NewScanner(file)
-	var vertexes []Vector
+	var vertexes []Vertex
 	var triangles []*Triangle
 	for _, element := range elements {
 		for i := 0; i < element.count; i++ {
@@ -156,28 +183,28 @@ func loadPlyAscii(file *os.File, elements []plyElement) (*Mesh, error) {
 			line := scanner.Text()
 			f := strings.Fields(line)
 			fi := 0
-			vertex := Vector{}
+			vertex := Vertex{}
 			for _, property := range element.properties {
 				if property.name == "x" {
-					vertex.X, _ = strconv.ParseFloat(f[fi], 64)
-					vertex.X, _ = strconv.ParseFloat(f[fi], 64) // 解析x坐标
+					vertex.Position.X, _ = strconv.ParseFloat(f[fi], 64) // 解析x坐标
 				}
 				if property.name == "y" {
-					vertex.Y, _ = strconv.ParseFloat(f[fi], 64)
-					vertex.Y, _ = strconv.ParseFloat(f[fi], 64) // 解析y坐标
+					vertex.Position.Y, _ = strconv.ParseFloat(f[fi], 64) // 解析y坐标
 				}
 				if property.name == "z" {
-					vertex.Z, _ = strconv.ParseFloat(f[fi], 64)
-					vertex.Z, _ = strconv.ParseFloat(f[fi], 64) // 解析z坐标
+					vertex.Position.Z, _ = strconv.ParseFloat(f[fi], 64) // 解析z坐标
+				}
+				if scalar != "" && property.name == scalar {
+					vertex.Scalar, _ = strconv.ParseFloat(f[fi], 64) // 解析标量
 				}
 				if property.name == "vertex_indices" {
 					i1, _ := strconv.ParseInt(f[fi+1], 0, 0)
 					i2, _ := strconv.ParseInt(f[fi+2], 0, 0)
 					i3, _ := strconv.ParseInt(f[fi+3], 0, 0)
 					t := Triangle{}
-					t.V1.Position = vertexes[i1]
-					t.V2.Position = vertexes[i2]
-					t.V3.Position = vertexes[i3]
+					t.V1 = vertexes[i1]
+					t.V2 = vertexes[i2]
+					t.V3 = vertexes[i3]
 					t.FixNormals()
 					triangles = append(triangles, &t)
 					fi += 3
@@ -185,7 +212,6 @@ func loadPlyAscii(file *os.File, elements []plyElement) (*Mesh, error) {
 				fi++
 			}
 			if element.name == "vertex" {
-				vertexes = append(vertexes, vertex)
 				vertexes = append(vertexes, vertex) // 添加顶点
 			}
 		}
@@ -194,13 +220,13 @@ func loadPlyAscii(file *os.File, elements []plyElement) (*Mesh, error) {
 }
 
 // 从PLY文件中加载网格（二进制格式）
-func loadPlyBinary(file *os.File, elements []plyElement, order binary.ByteOrder) (*Mesh, error) {
-	var vertexes []Vector     // 顶点列表
+func loadPlyBinary(file *os.File, elements []plyElement, scalar string, order binary.ByteOrder) (*Mesh, error) {
+	var vertexes []Vertex     // 顶点列表
 	var triangles []*Triangle // 三角形列表
 	for _, element := range elements {
 		for i := 0; i < element.count; i++ {
-			var vertex Vector   // 顶点
-			var points []Vector // 点列表
+			var vertex Vertex   // 顶点
+			var points []Vertex // 点列表
 			for _, property := range element.properties {
 				if property.countType == plyNone { // 非列表类型
 					value, err := readPlyFloat(file, order, property.dataType) // 读取浮点数
@@ -208,13 +234,16 @@ func loadPlyBinary(file *os.File, elements []plyElement, order binary.ByteOrder)
 						return nil, err
 					}
 					if property.name == "x" { // x坐标
-						vertex.X = value // 设置x坐标
+						vertex.Position.X = value // 设置x坐标
 					}
 					if property.name == "y" { // y坐标
-						vertex.Y = value // 设置y坐标
+						vertex.Position.Y = value // 设置y坐标
 					}
 					if property.name == "z" { // z坐标
-						vertex.Z = value // 设置z坐标
+						vertex.Position.Z = value // 设置z坐标
+					}
+					if scalar != "" && property.name == scalar { // 标量
+						vertex.Scalar = value // 设置标量
 					}
 				} else { // 列表类型
 					count, err := readPlyInt(file, order, property.countType) // 读取计数
@@ -237,9 +266,9 @@ func loadPlyBinary(file *os.File, elements []plyElement, order binary.ByteOrder)
 			}
 			if element.name == "face" { // 面
 				t := Triangle{}
-				t.V1.Position = points[0]         // 设置第一个顶点
-				t.V2.Position = points[1]         // 设置第二个顶点
-				t.V3.Position = points[2]         // 设置第三个顶点
+				t.V1 = points[0]                  // 设置第一个顶点
+				t.V2 = points[1]                  // 设置第二个顶点
+				t.V3 = points[2]                  // 设置第三个顶点
 				t.FixNormals()                    // 修正法向量
 				triangles = append(triangles, &t) // 添加三角形
 			}
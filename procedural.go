@@ -0,0 +1,222 @@
+package fauxgl
+
+import "math"
+
+// CheckerTexture 棋盘格纹理
+type CheckerTexture struct {
+	Scale  float64 // 纹理坐标每单位的格子数
+	Color1 Color   // 第一种格子的颜色
+	Color2 Color   // 第二种格子的颜色
+}
+
+// NewCheckerTexture 创建棋盘格纹理
+func NewCheckerTexture(scale float64, color1, color2 Color) *CheckerTexture {
+	return &CheckerTexture{scale, color1, color2}
+}
+
+// Sample 对纹理进行采样
+func (t *CheckerTexture) Sample(u, v float64) Color {
+	x := int(math.Floor(u * t.Scale))
+	y := int(math.Floor(v * t.Scale))
+	if (x+y)&1 == 0 {
+		return t.Color1
+	}
+	return t.Color2
+}
+
+// BilinearSample 对纹理进行采样，与 Sample 相同
+func (t *CheckerTexture) BilinearSample(u, v float64) Color {
+	return t.Sample(u, v)
+}
+
+// GridTexture 网格线纹理
+type GridTexture struct {
+	Scale           float64 // 纹理坐标每单位的格子数
+	LineWidth       float64 // 线宽，占格子边长的比例
+	LineColor       Color   // 线条颜色
+	BackgroundColor Color   // 背景颜色
+}
+
+// NewGridTexture 创建网格线纹理
+func NewGridTexture(scale, lineWidth float64, lineColor, backgroundColor Color) *GridTexture {
+	return &GridTexture{scale, lineWidth, lineColor, backgroundColor}
+}
+
+// Sample 对纹理进行采样
+func (t *GridTexture) Sample(u, v float64) Color {
+	x := u * t.Scale
+	y := v * t.Scale
+	dx := math.Abs(x - math.Floor(x+0.5))
+	dy := math.Abs(y - math.Floor(y+0.5))
+	if dx < t.LineWidth/2 || dy < t.LineWidth/2 {
+		return t.LineColor
+	}
+	return t.BackgroundColor
+}
+
+// BilinearSample 对纹理进行采样，与 Sample 相同
+func (t *GridTexture) BilinearSample(u, v float64) Color {
+	return t.Sample(u, v)
+}
+
+// UVTexture 纹理坐标调试纹理
+// 红色和绿色通道分别表示 u 和 v 的小数部分，并叠加棋盘格以显示拉伸和翻转
+type UVTexture struct {
+	Scale float64 // 纹理坐标每单位的格子数
+}
+
+// NewUVTexture 创建纹理坐标调试纹理
+func NewUVTexture(scale float64) *UVTexture {
+	return &UVTexture{scale}
+}
+
+// Sample 对纹理进行采样
+func (t *UVTexture) Sample(u, v float64) Color {
+	fu := u - math.Floor(u)
+	fv := v - math.Floor(v)
+	c := Color{fu, fv, 0.5, 1}
+	x := int(math.Floor(u * t.Scale))
+	y := int(math.Floor(v * t.Scale))
+	if (x+y)&1 != 0 {
+		c = c.MulScalar(0.75).Alpha(1)
+	}
+	return c
+}
+
+// BilinearSample 对纹理进行采样，与 Sample 相同
+func (t *UVTexture) BilinearSample(u, v float64) Color {
+	return t.Sample(u, v)
+}
+
+// NoiseTexture 分形 Perlin 噪声纹理
+type NoiseTexture struct {
+	Noise   *Noise  // 噪声
+	Scale   float64 // 噪声频率
+	Octaves int     // 分形层数
+	Color1  Color   // 噪声最小值处的颜色
+	Color2  Color   // 噪声最大值处的颜色
+}
+
+// NewNoiseTexture 创建分形 Perlin 噪声纹理
+func NewNoiseTexture(seed int64, scale float64, color1, color2 Color) *NoiseTexture {
+	return &NoiseTexture{NewNoise(seed), scale, 4, color1, color2}
+}
+
+// at 返回 (x, y, z) 处的颜色
+func (t *NoiseTexture) at(x, y, z float64) Color {
+	s := t.Scale
+	n := t.Noise.Fractal(x*s, y*s, z*s, t.Octaves)
+	return t.Color1.Lerp(t.Color2, Clamp(n*0.5+0.5, 0, 1))
+}
+
+// Sample 对纹理进行采样
+func (t *NoiseTexture) Sample(u, v float64) Color {
+	return t.at(u, v, 0)
+}
+
+// BilinearSample 对纹理进行采样，与 Sample 相同
+func (t *NoiseTexture) BilinearSample(u, v float64) Color {
+	return t.at(u, v, 0)
+}
+
+// WorleyTexture Worley 细胞噪声纹理
+type WorleyTexture struct {
+	Noise  *WorleyNoise // 噪声
+	Scale  float64      // 每单位的细胞数
+	Color1 Color        // 特征点处的颜色
+	Color2 Color        // 远离特征点处的颜色
+}
+
+// NewWorleyTexture 创建 Worley 细胞噪声纹理
+func NewWorleyTexture(seed int64, scale float64, color1, color2 Color) *WorleyTexture {
+	return &WorleyTexture{NewWorleyNoise(seed), scale, color1, color2}
+}
+
+// at 返回 (x, y, z) 处的颜色
+func (t *WorleyTexture) at(x, y, z float64) Color {
+	s := t.Scale
+	f1, _ := t.Noise.Distance(x*s, y*s, z*s)
+	return t.Color1.Lerp(t.Color2, Clamp(f1, 0, 1))
+}
+
+// Sample 对纹理进行采样
+func (t *WorleyTexture) Sample(u, v float64) Color {
+	return t.at(u, v, 0)
+}
+
+// BilinearSample 对纹理进行采样，与 Sample 相同
+func (t *WorleyTexture) BilinearSample(u, v float64) Color {
+	return t.at(u, v, 0)
+}
+
+// WoodTexture 木纹纹理，由同心年轮和噪声扰动组成，年轮围绕 z 轴
+type WoodTexture struct {
+	Noise      *Noise  // 噪声
+	Scale      float64 // 坐标缩放
+	Rings      float64 // 每单位距离的年轮数
+	Turbulence float64 // 噪声扰动强度
+	LightColor Color   // 浅色木质
+	DarkColor  Color   // 深色年轮
+}
+
+// NewWoodTexture 创建木纹纹理
+func NewWoodTexture(seed int64, scale float64) *WoodTexture {
+	light := HexColor("#D9A46B")
+	dark := HexColor("#8A5A2B")
+	return &WoodTexture{NewNoise(seed), scale, 8, 0.6, light, dark}
+}
+
+// at 返回 (x, y, z) 处的颜色
+func (t *WoodTexture) at(x, y, z float64) Color {
+	x, y, z = x*t.Scale, y*t.Scale, z*t.Scale
+	d := math.Hypot(x, y)*t.Rings + t.Turbulence*t.Noise.Fractal(x, y, z, 4)*t.Rings
+	d -= math.Floor(d)
+	// 年轮一侧清晰一侧渐变
+	return t.LightColor.Lerp(t.DarkColor, math.Pow(d, 3))
+}
+
+// Sample 对纹理进行采样
+func (t *WoodTexture) Sample(u, v float64) Color {
+	return t.at(u-0.5, v-0.5, 0)
+}
+
+// BilinearSample 对纹理进行采样，与 Sample 相同
+func (t *WoodTexture) BilinearSample(u, v float64) Color {
+	return t.Sample(u, v)
+}
+
+// MarbleTexture 大理石纹理，由正弦条纹和湍流扰动组成，条纹沿 x 轴变化
+type MarbleTexture struct {
+	Noise      *Noise  // 噪声
+	Scale      float64 // 坐标缩放
+	Stripes    float64 // 每单位距离的条纹数
+	Turbulence float64 // 湍流扰动强度
+	Octaves    int     // 湍流分形层数
+	BaseColor  Color   // 基底颜色
+	VeinColor  Color   // 纹理颜色
+}
+
+// NewMarbleTexture 创建大理石纹理
+func NewMarbleTexture(seed int64, scale float64) *MarbleTexture {
+	base := HexColor("#EDEBE6")
+	vein := HexColor("#5C6670")
+	return &MarbleTexture{NewNoise(seed), scale, 2, 6, 6, base, vein}
+}
+
+// at 返回 (x, y, z) 处的颜色
+func (t *MarbleTexture) at(x, y, z float64) Color {
+	x, y, z = x*t.Scale, y*t.Scale, z*t.Scale
+	a := x*t.Stripes*math.Pi + t.Turbulence*t.Noise.Turbulence(x, y, z, t.Octaves)
+	s := math.Sin(a)*0.5 + 0.5
+	return t.VeinColor.Lerp(t.BaseColor, math.Pow(s, 0.4))
+}
+
+// Sample 对纹理进行采样
+func (t *MarbleTexture) Sample(u, v float64) Color {
+	return t.at(u, v, 0)
+}
+
+// BilinearSample 对纹理进行采样，与 Sample 相同
+func (t *MarbleTexture) BilinearSample(u, v float64) Color {
+	return t.at(u, v, 0)
+}
@@ -0,0 +1,176 @@
+package fauxgl
+
+import (
+	"image"
+	"math"
+)
+
+// ResolveFilter 表示超采样降采样使用的滤波器
+type ResolveFilter int
+
+const (
+	_ ResolveFilter = iota
+	// FilterBox 盒式滤波器
+	FilterBox
+	// FilterTent 三角（帐篷）滤波器
+	FilterTent
+	// FilterLanczos Lanczos-3 滤波器
+	FilterLanczos
+	// FilterMitchell Mitchell-Netravali 滤波器（B = C = 1/3）
+	FilterMitchell
+)
+
+// support 返回滤波器的半径（以输出像素为单位）
+func (f ResolveFilter) support() float64 {
+	switch f {
+	case FilterTent:
+		return 1
+	case FilterLanczos:
+		return 3
+	case FilterMitchell:
+		return 2
+	default:
+		return 0.5
+	}
+}
+
+// weight 返回滤波器在距离 x（以输出像素为单位）处的权重
+func (f ResolveFilter) weight(x float64) float64 {
+	x = math.Abs(x)
+	switch f {
+	case FilterTent:
+		return math.Max(1-x, 0)
+	case FilterLanczos:
+		if x == 0 {
+			return 1
+		}
+		if x >= 3 {
+			return 0
+		}
+		px := math.Pi * x
+		return 3 * math.Sin(px) * math.Sin(px/3) / (px * px)
+	case FilterMitchell:
+		const b = 1.0 / 3
+		const c = 1.0 / 3
+		if x < 1 {
+			return ((12-9*b-6*c)*x*x*x + (-18+12*b+6*c)*x*x + (6 - 2*b)) / 6
+		}
+		if x < 2 {
+			return ((-b-6*c)*x*x*x + (6*b+30*c)*x*x + (-12*b-48*c)*x + (8*b + 24*c)) / 6
+		}
+		return 0
+	default:
+		if x <= 0.5 {
+			return 1
+		}
+		return 0
+	}
+}
+
+// resolveTap 降采样中单个源像素的索引和归一化权重
+type resolveTap struct {
+	index  int
+	weight float64
+}
+
+// resolveTaps 返回将 n 个源像素降采样 factor 倍时每个输出像素的采样点
+func resolveTaps(n, factor int, filter ResolveFilter) [][]resolveTap {
+	m := n / factor
+	f := float64(factor)
+	radius := filter.support() * f
+	result := make([][]resolveTap, m)
+	for o := 0; o < m; o++ {
+		center := (float64(o) + 0.5) * f
+		lo := int(math.Floor(center - radius))
+		hi := int(math.Ceil(center + radius))
+		var taps []resolveTap
+		var total float64
+		for s := lo; s <= hi; s++ {
+			w := filter.weight((float64(s) + 0.5 - center) / f)
+			if w == 0 {
+				continue
+			}
+			taps = append(taps, resolveTap{ClampInt(s, 0, n-1), w})
+			total += w
+		}
+		for i := range taps {
+			taps[i].weight /= total
+		}
+		result[o] = taps
+	}
+	return result
+}
+
+// srgbToLinearTable 8 位 sRGB 值到线性值的查找表
+var srgbToLinearTable = func() [256]float64 {
+	var table [256]float64
+	for i := range table {
+		table[i] = srgbToLinear(float64(i) / 255)
+	}
+	return table
+}()
+
+// Resolve 将颜色缓冲区按 factor 倍降采样，返回 Width/factor x Height/factor 的图像
+// 颜色缓冲区被视为 sRGB 编码的，滤波在线性空间中以预乘透明度的颜色进行
+func (dc *Context) Resolve(factor int, filter ResolveFilter) *image.NRGBA {
+	if factor < 1 {
+		factor = 1
+	}
+	w := dc.Width / factor
+	h := dc.Height / factor
+	src := dc.ColorBuffer
+
+	// 解码为线性、预乘透明度的颜色
+	linear := make([]Color, dc.Width*dc.Height)
+	parallelFor(dc.Height, func(y int) {
+		i := src.PixOffset(0, y)
+		k := y * dc.Width
+		for x := 0; x < dc.Width; x++ {
+			p := src.Pix[i : i+4 : i+4]
+			a := float64(p[3]) / 255
+			linear[k] = Color{
+				srgbToLinearTable[p[0]] * a,
+				srgbToLinearTable[p[1]] * a,
+				srgbToLinearTable[p[2]] * a,
+				a,
+			}
+			i += 4
+			k++
+		}
+	})
+
+	// 水平方向
+	xtaps := resolveTaps(dc.Width, factor, filter)
+	horizontal := make([]Color, w*dc.Height)
+	parallelFor(dc.Height, func(y int) {
+		row := linear[y*dc.Width : (y+1)*dc.Width]
+		for x, taps := range xtaps {
+			var c Color
+			for _, t := range taps {
+				c = c.Add(row[t.index].MulScalar(t.weight))
+			}
+			horizontal[y*w+x] = c
+		}
+	})
+
+	// 垂直方向
+	ytaps := resolveTaps(dc.Height, factor, filter)
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	parallelFor(h, func(y int) {
+		taps := ytaps[y]
+		for x := 0; x < w; x++ {
+			var c Color
+			for _, t := range taps {
+				c = c.Add(horizontal[t.index*w+x].MulScalar(t.weight))
+			}
+			// 负权重可能产生越界值，透明度取整以免不透明像素因舍入误差变为半透明
+			a := math.Round(Clamp(c.A, 0, 1)*255) / 255
+			if a > 0 {
+				c = c.DivScalar(c.A)
+			}
+			c = c.SRGB().Alpha(a)
+			dst.SetNRGBA(x, y, c.NRGBA())
+		}
+	})
+	return dst
+}
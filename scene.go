@@ -0,0 +1,139 @@
+package fauxgl
+
+// Material 材质，绘制场景时用于创建冯氏着色器
+type Material struct {
+	Color         Color   // 物体颜色，为 Discard 时使用顶点颜色
+	AmbientColor  Color   // 环境光颜色
+	DiffuseColor  Color   // 漫反射颜色
+	SpecularColor Color   // 镜面反射颜色
+	Texture       Texture // 纹理，非 nil 时代替物体颜色
+	SpecularPower float64 // 镜面反射指数
+}
+
+// NewMaterial 创建颜色为 color 的材质，光照参数与 NewPhongShader 相同
+func NewMaterial(color Color) *Material {
+	ambient := Color{0.2, 0.2, 0.2, 1}
+	diffuse := Color{0.8, 0.8, 0.8, 1}
+	specular := Color{1, 1, 1, 1}
+	return &Material{color, ambient, diffuse, specular, nil, 32}
+}
+
+// Shader 返回使用该材质的冯氏着色器
+func (m *Material) Shader(matrix Matrix, lightDirection, cameraPosition Vector) *PhongShader {
+	shader := NewPhongShader(matrix, lightDirection, cameraPosition)
+	shader.ObjectColor = m.Color
+	shader.AmbientColor = m.AmbientColor
+	shader.DiffuseColor = m.DiffuseColor
+	shader.SpecularColor = m.SpecularColor
+	shader.Texture = m.Texture
+	shader.SpecularPower = m.SpecularPower
+	return shader
+}
+
+// Node 场景节点，包含相对于父节点的变换、网格、材质和子节点
+type Node struct {
+	Name     string    // 名称
+	Matrix   Matrix    // 相对于父节点的局部变换
+	Mesh     *Mesh     // 网格，可以为 nil
+	Material *Material // 材质，为 nil 时继承父节点的材质
+	Shader   Shader    // 自定义着色器，优先于材质；其输入顶点已变换到世界坐标，矩阵应为相机的 Matrix
+	ID       int       // 写入对象缓冲区的对象编号，为 0 时继承父节点的编号
+	Children []*Node   // 子节点
+}
+
+// NewNode 创建空的场景节点
+func NewNode() *Node {
+	return &Node{Matrix: Identity()}
+}
+
+// NewMeshNode 创建包含网格和材质的场景节点
+func NewMeshNode(mesh *Mesh, material *Material) *Node {
+	return &Node{Matrix: Identity(), Mesh: mesh, Material: material}
+}
+
+// Add 添加子节点
+func (n *Node) Add(children ...*Node) {
+	n.Children = append(n.Children, children...)
+}
+
+// Transform 将变换 matrix 追加到局部变换之后
+func (n *Node) Transform(matrix Matrix) {
+	n.Matrix = matrix.Mul(n.Matrix)
+}
+
+// walk 深度优先遍历节点，fn 接收节点、世界变换以及继承后的材质、着色器和对象编号
+func (n *Node) walk(parent Matrix, material *Material, shader Shader, id int, fn func(n *Node, world Matrix, material *Material, shader Shader, id int)) {
+	world := parent.Mul(n.Matrix)
+	if n.Material != nil {
+		material = n.Material
+		shader = nil
+	}
+	if n.Shader != nil {
+		shader = n.Shader
+	}
+	if n.ID != 0 {
+		id = n.ID
+	}
+	fn(n, world, material, shader, id)
+	for _, child := range n.Children {
+		child.walk(world, material, shader, id, fn)
+	}
+}
+
+// Scene 场景，由节点树和光照组成
+type Scene struct {
+	Root           *Node  // 根节点
+	LightDirection Vector // 指向光源的方向
+}
+
+// NewScene 创建空场景
+func NewScene() *Scene {
+	return &Scene{NewNode(), V(0.25, 0.5, 1).Normalize()}
+}
+
+// Add 向根节点添加子节点
+func (s *Scene) Add(nodes ...*Node) {
+	s.Root.Add(nodes...)
+}
+
+// BoundingBox 返回场景中所有网格在世界坐标中的包围盒，可以传给 Camera.FitToBox
+func (s *Scene) BoundingBox() Box {
+	var boxes []Box
+	s.Root.walk(Identity(), nil, nil, 0, func(n *Node, world Matrix, material *Material, shader Shader, id int) {
+		if n.Mesh != nil && len(n.Mesh.Triangles)+len(n.Mesh.Lines) > 0 {
+			boxes = append(boxes, n.Mesh.BoundingBox().Transform(world))
+		}
+	})
+	return BoxForBoxes(boxes)
+}
+
+// DrawScene 使用相机 camera 绘制场景，依次组合各节点的变换并以其材质或着色器绘制网格
+// 没有材质的节点使用白色材质；上下文的着色器、对象编号和正面方向在绘制后恢复
+func (dc *Context) DrawScene(scene *Scene, camera *Camera) RasterizeInfo {
+	matrix := camera.Matrix()
+	defaultMaterial := NewMaterial(White)
+	previousShader, previousID, frontFace := dc.Shader, dc.ObjectID, dc.FrontFace
+	var result RasterizeInfo
+	scene.Root.walk(Identity(), defaultMaterial, nil, 0, func(n *Node, world Matrix, material *Material, custom Shader, id int) {
+		if n.Mesh == nil {
+			return
+		}
+		if custom == nil {
+			custom = material.Shader(matrix, scene.LightDirection, camera.Position)
+		}
+		dc.Shader = Compose(custom).WithVertex(TransformVertex(world))
+		dc.ObjectID = id
+		// 镜像变换会反转三角形的环绕方向
+		dc.FrontFace = frontFace
+		if world.Determinant() < 0 {
+			if frontFace == FaceCW {
+				dc.FrontFace = FaceCCW
+			} else {
+				dc.FrontFace = FaceCW
+			}
+		}
+		result = result.Add(dc.DrawMesh(n.Mesh))
+	})
+	dc.Shader, dc.ObjectID, dc.FrontFace = previousShader, previousID, frontFace
+	return result
+}
@@ -8,6 +8,16 @@ type Shader interface {
 	Fragment(Vertex) Color // 片元着色器
 }
 
+// DerivativeShader 是需要屏幕空间偏导数的着色器
+// 光栅化器以 2x2 像素块的方式在相邻像素处对顶点进行插值，供着色器计算偏导数
+type DerivativeShader interface {
+	Shader
+	// Derivatives 返回着色器当前是否需要偏导数
+	Derivatives() bool
+	// FragmentDerivatives 片元着色器，right 和 below 分别是像素 (x+1, y) 和 (x, y+1) 处的插值顶点
+	FragmentDerivatives(v, right, below Vertex) Color
+}
+
 // SolidColorShader 渲染单一颜色
 type SolidColorShader struct {
 	Matrix Matrix // 变换矩阵
@@ -52,6 +62,16 @@ func (shader *TextureShader) Fragment(v Vertex) Color {
 	return shader.Texture.BilinearSample(v.Texture.X, v.Texture.Y)
 }
 
+// Derivatives 纹理支持 mipmap 时需要偏导数
+func (shader *TextureShader) Derivatives() bool {
+	return hasMipmaps(shader.Texture)
+}
+
+// FragmentDerivatives 使用三线性采样的片元着色器
+func (shader *TextureShader) FragmentDerivatives(v, right, below Vertex) Color {
+	return trilinearSample(shader.Texture, v, right, below)
+}
+
 // PhongShader 实现冯氏着色法
 type PhongShader struct {
 	Matrix         Matrix
@@ -62,7 +82,9 @@ type PhongShader struct {
 	DiffuseColor   Color
 	SpecularColor  Color
 	Texture        Texture
+	SolidTexture   SolidTexture // 实体纹理，按顶点位置采样
 	SpecularPower  float64
+	HDR            bool // 不将光照结果截断到白色，用于高动态范围渲染
 }
 // NewPhongShader 创建一个实现冯氏着色法的着色器
 func NewPhongShader(matrix Matrix, lightDirection, cameraPosition Vector) *PhongShader {
@@ -71,7 +93,7 @@ func NewPhongShader(matrix Matrix, lightDirection, cameraPosition Vector) *Phong
 	specular := Color{1, 1, 1, 1}
 	return &PhongShader{
 		matrix, lightDirection, cameraPosition,
-		Discard, ambient, diffuse, specular, nil, 32}
+		Discard, ambient, diffuse, specular, nil, nil, 32, false}
 }
 
 // Vertex 顶点着色器
@@ -82,7 +104,6 @@ func (shader *PhongShader) Vertex(v Vertex) Vertex {
 
 // Fragment 片元着色器
 func (shader *PhongShader) Fragment(v Vertex) Color {
-	light := shader.AmbientColor
 	color := v.Color
 	if shader.ObjectColor != Discard {
 		color = shader.ObjectColor
@@ -90,6 +111,26 @@ func (shader *PhongShader) Fragment(v Vertex) Color {
 	if shader.Texture != nil {
 		color = shader.Texture.BilinearSample(v.Texture.X, v.Texture.Y)
 	}
+	if shader.SolidTexture != nil {
+		color = shader.SolidTexture.SampleSolid(v.Position, v.Normal)
+	}
+	return shader.shade(v, color)
+}
+
+// Derivatives 纹理支持 mipmap 时需要偏导数，实体纹理优先于纹理，此时不需要偏导数
+func (shader *PhongShader) Derivatives() bool {
+	return shader.SolidTexture == nil && shader.Texture != nil && hasMipmaps(shader.Texture)
+}
+
+// FragmentDerivatives 使用三线性采样的片元着色器
+func (shader *PhongShader) FragmentDerivatives(v, right, below Vertex) Color {
+	color := trilinearSample(shader.Texture, v, right, below)
+	return shader.shade(v, color)
+}
+
+// shade 对物体颜色 color 计算光照
+func (shader *PhongShader) shade(v Vertex, color Color) Color {
+	light := shader.AmbientColor
 	diffuse := math.Max(v.Normal.Dot(shader.LightDirection), 0)
 	light = light.Add(shader.DiffuseColor.MulScalar(diffuse))
 	if diffuse > 0 && shader.SpecularPower > 0 {
@@ -101,5 +142,66 @@ func (shader *PhongShader) Fragment(v Vertex) Color {
 			light = light.Add(shader.SpecularColor.MulScalar(specular))
 		}
 	}
+	if shader.HDR {
+		return color.Mul(light).Alpha(color.A)
+	}
 	return color.Mul(light).Min(White).Alpha(color.A)
 }
+
+// GouraudShader 实现高洛德着色法，在顶点着色器中计算光照并对结果颜色进行插值
+type GouraudShader struct {
+	Matrix         Matrix
+	LightDirection Vector
+	CameraPosition Vector
+	ObjectColor    Color
+	AmbientColor   Color
+	DiffuseColor   Color
+	SpecularColor  Color
+	Texture        Texture
+	SpecularPower  float64
+}
+
+// NewGouraudShader 创建一个实现高洛德着色法的着色器
+func NewGouraudShader(matrix Matrix, lightDirection, cameraPosition Vector) *GouraudShader {
+	ambient := Color{0.2, 0.2, 0.2, 1}
+	diffuse := Color{0.8, 0.8, 0.8, 1}
+	specular := Color{1, 1, 1, 1}
+	return &GouraudShader{
+		matrix, lightDirection, cameraPosition,
+		Discard, ambient, diffuse, specular, nil, 32}
+}
+
+// Vertex 顶点着色器
+func (shader *GouraudShader) Vertex(v Vertex) Vertex {
+	v.Output = shader.Matrix.MulPositionW(v.Position)
+	light := shader.AmbientColor
+	color := v.Color
+	if shader.ObjectColor != Discard {
+		color = shader.ObjectColor
+	}
+	if shader.Texture != nil {
+		color = White
+	}
+	diffuse := math.Max(v.Normal.Dot(shader.LightDirection), 0)
+	light = light.Add(shader.DiffuseColor.MulScalar(diffuse))
+	if diffuse > 0 && shader.SpecularPower > 0 {
+		camera := shader.CameraPosition.Sub(v.Position).Normalize()
+		reflected := shader.LightDirection.Negate().Reflect(v.Normal)
+		specular := math.Max(camera.Dot(reflected), 0)
+		if specular > 0 {
+			specular = math.Pow(specular, shader.SpecularPower)
+			light = light.Add(shader.SpecularColor.MulScalar(specular))
+		}
+	}
+	v.Color = color.Mul(light).Min(White).Alpha(color.A)
+	return v
+}
+
+// Fragment 片元着色器
+func (shader *GouraudShader) Fragment(v Vertex) Color {
+	if shader.Texture != nil {
+		color := shader.Texture.BilinearSample(v.Texture.X, v.Texture.Y)
+		return color.Mul(v.Color).Min(White)
+	}
+	return v.Color
+}
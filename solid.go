@@ -0,0 +1,99 @@
+package fauxgl
+
+import "math"
+
+// SolidTexture 按三维坐标采样的实体纹理，适用于没有纹理坐标的网格
+type SolidTexture interface {
+	// SampleSolid 对位置 position 处进行采样，normal 是该处的法线
+	// 纯实体纹理只使用位置，投影类纹理还会使用法线
+	SampleSolid(position, normal Vector) Color
+}
+
+// SampleSolid 对三维棋盘格进行采样
+func (t *CheckerTexture) SampleSolid(position, normal Vector) Color {
+	p := position.MulScalar(t.Scale).Floor()
+	if (int(p.X)+int(p.Y)+int(p.Z))&1 == 0 {
+		return t.Color1
+	}
+	return t.Color2
+}
+
+// SampleSolid 对三维噪声进行采样
+func (t *NoiseTexture) SampleSolid(position, normal Vector) Color {
+	return t.at(position.X, position.Y, position.Z)
+}
+
+// SampleSolid 对三维细胞噪声进行采样
+func (t *WorleyTexture) SampleSolid(position, normal Vector) Color {
+	return t.at(position.X, position.Y, position.Z)
+}
+
+// SampleSolid 对三维木纹进行采样
+func (t *WoodTexture) SampleSolid(position, normal Vector) Color {
+	return t.at(position.X, position.Y, position.Z)
+}
+
+// SampleSolid 对三维大理石纹理进行采样
+func (t *MarbleTexture) SampleSolid(position, normal Vector) Color {
+	return t.at(position.X, position.Y, position.Z)
+}
+
+// TriplanarTexture 将二维纹理沿三个坐标轴投影，并按法线方向混合
+type TriplanarTexture struct {
+	Texture   Texture // 二维纹理
+	Scale     float64 // 每单位长度的纹理重复次数
+	Sharpness float64 // 混合锐度，越大投影之间的过渡越窄
+}
+
+// NewTriplanarTexture 创建三平面投影纹理
+func NewTriplanarTexture(texture Texture, scale float64) *TriplanarTexture {
+	return &TriplanarTexture{texture, scale, 4}
+}
+
+// SampleSolid 对三平面投影纹理进行采样
+func (t *TriplanarTexture) SampleSolid(position, normal Vector) Color {
+	w := normal.Abs()
+	w = Vector{
+		math.Pow(w.X, t.Sharpness),
+		math.Pow(w.Y, t.Sharpness),
+		math.Pow(w.Z, t.Sharpness),
+	}
+	sum := w.X + w.Y + w.Z
+	if sum == 0 {
+		w, sum = Vector{0, 0, 1}, 1
+	}
+	p := position.MulScalar(t.Scale)
+	c := Color{}
+	if w.X > 0 {
+		c = c.Add(t.Texture.BilinearSample(p.Y, p.Z).MulScalar(w.X / sum))
+	}
+	if w.Y > 0 {
+		c = c.Add(t.Texture.BilinearSample(p.X, p.Z).MulScalar(w.Y / sum))
+	}
+	if w.Z > 0 {
+		c = c.Add(t.Texture.BilinearSample(p.X, p.Y).MulScalar(w.Z / sum))
+	}
+	return c
+}
+
+// SolidTextureShader 按顶点位置对实体纹理采样
+type SolidTextureShader struct {
+	Matrix  Matrix
+	Texture SolidTexture
+}
+
+// NewSolidTextureShader 创建一个渲染实体纹理的着色器
+func NewSolidTextureShader(matrix Matrix, texture SolidTexture) *SolidTextureShader {
+	return &SolidTextureShader{matrix, texture}
+}
+
+// Vertex 顶点着色器
+func (shader *SolidTextureShader) Vertex(v Vertex) Vertex {
+	v.Output = shader.Matrix.MulPositionW(v.Position)
+	return v
+}
+
+// Fragment 片元着色器
+func (shader *SolidTextureShader) Fragment(v Vertex) Color {
+	return shader.Texture.SampleSolid(v.Position, v.Normal)
+}
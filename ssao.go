@@ -0,0 +1,260 @@
+package fauxgl
+
+import (
+	"math"
+	"math/rand"
+)
+
+// SSAO 屏幕空间环境光遮蔽参数
+type SSAO struct {
+	Radius     float64 // 视空间中的采样半径
+	Samples    int     // 每个像素的采样数
+	Strength   float64 // 遮蔽强度，1 表示完全遮蔽时变为黑色
+	Bias       float64 // 深度偏移，避免平面自遮蔽
+	BlurRadius int     // 双边模糊的像素半径，0 表示不模糊
+	kernel     []Vector
+}
+
+// NewSSAO 创建屏幕空间环境光遮蔽
+func NewSSAO(radius float64, samples int, strength float64) *SSAO {
+	return &SSAO{radius, samples, strength, radius * 0.025, 2, nil}
+}
+
+// ssaoNoise 4x4 平铺的随机旋转向量，使相邻像素使用不同的采样方向
+var ssaoNoise = func() [16]Vector {
+	var noise [16]Vector
+	rnd := rand.New(rand.NewSource(0))
+	for i := range noise {
+		a := rnd.Float64() * 2 * math.Pi
+		noise[i] = Vector{math.Cos(a), math.Sin(a), 0}
+	}
+	return noise
+}()
+
+// samples 返回单位半球（z > 0）内的采样核，靠近中心的采样更密集
+func (s *SSAO) samples() []Vector {
+	if len(s.kernel) == s.Samples {
+		return s.kernel
+	}
+	rnd := rand.New(rand.NewSource(1))
+	kernel := make([]Vector, s.Samples)
+	for i := range kernel {
+		var v Vector
+		for {
+			v = Vector{rnd.Float64()*2 - 1, rnd.Float64()*2 - 1, rnd.Float64()}
+			if l := v.Length(); l > 1e-3 && l <= 1 {
+				break
+			}
+		}
+		t := float64(i+1) / float64(s.Samples)
+		kernel[i] = v.MulScalar(0.1 + 0.9*t*t)
+	}
+	s.kernel = kernel
+	return kernel
+}
+
+// smoothstep 在 [e0, e1] 之间平滑插值的阶跃函数
+func smoothstep(e0, e1, x float64) float64 {
+	t := Clamp((x-e0)/(e1-e0), 0, 1)
+	return t * t * (3 - 2*t)
+}
+
+// viewPositions 将深度缓冲区还原为视空间坐标，未被绘制的像素为零向量，ok 中对应元素为 false
+func (dc *Context) viewPositions(projection Matrix) (positions []Vector, ok []bool) {
+	inverse := projection.Inverse()
+	positions = make([]Vector, dc.Width*dc.Height)
+	ok = make([]bool, dc.Width*dc.Height)
+	parallelFor(dc.Height, func(y int) {
+		for x := 0; x < dc.Width; x++ {
+			i := y*dc.Width + x
+			z := dc.DepthBuffer[i]
+			if z == math.MaxFloat64 {
+				continue
+			}
+			positions[i] = dc.unproject(x, y, z, inverse)
+			ok[i] = true
+		}
+	})
+	return
+}
+
+// viewNormals 由视空间坐标的差分重建视空间法线，法线总是朝向相机
+// 每个方向选择深度变化较小的一侧差分，以避免物体边缘处的法线错误
+func (dc *Context) viewNormals(positions []Vector, ok []bool) []Vector {
+	w, h := dc.Width, dc.Height
+	normals := make([]Vector, w*h)
+	// difference 返回像素 i 处沿 (i-step, i+step) 方向的差分
+	difference := func(i, step int, hasPrev, hasNext bool) (Vector, bool) {
+		hasPrev = hasPrev && ok[i-step]
+		hasNext = hasNext && ok[i+step]
+		p := positions[i]
+		switch {
+		case hasPrev && hasNext:
+			d0 := p.Sub(positions[i-step])
+			d1 := positions[i+step].Sub(p)
+			if math.Abs(d0.Z) < math.Abs(d1.Z) {
+				return d0, true
+			}
+			return d1, true
+		case hasPrev:
+			return p.Sub(positions[i-step]), true
+		case hasNext:
+			return positions[i+step].Sub(p), true
+		}
+		return Vector{}, false
+	}
+	parallelFor(h, func(y int) {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			if !ok[i] {
+				continue
+			}
+			dx, okx := difference(i, 1, x > 0, x < w-1)
+			dy, oky := difference(i, w, y > 0, y < h-1)
+			if !okx || !oky {
+				normals[i] = Vector{0, 0, 1}
+				continue
+			}
+			n := dx.Cross(dy)
+			l := n.Length()
+			if l == 0 {
+				normals[i] = Vector{0, 0, 1}
+				continue
+			}
+			n = n.DivScalar(l)
+			if n.Dot(positions[i]) > 0 {
+				n = n.Negate()
+			}
+			normals[i] = n
+		}
+	})
+	return normals
+}
+
+// Compute 计算每个像素的环境光可见度，1 表示无遮蔽，0 表示完全遮蔽
+// projection 是绘制时使用的投影矩阵
+// normals 是视空间法线，为 nil 时由深度缓冲区重建
+func (s *SSAO) Compute(dc *Context, projection Matrix, normals []Vector) []float64 {
+	w, h := dc.Width, dc.Height
+	positions, ok := dc.viewPositions(projection)
+	if normals == nil {
+		normals = dc.viewNormals(positions, ok)
+	}
+	kernel := s.samples()
+	occlusion := make([]float64, w*h)
+	parallelFor(h, func(y int) {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			if !ok[i] || len(kernel) == 0 {
+				occlusion[i] = 1
+				continue
+			}
+			p := positions[i]
+			n := normals[i]
+			// 以随机向量构造切线空间
+			r := ssaoNoise[(y&3)*4+(x&3)]
+			t := r.Sub(n.MulScalar(r.Dot(n)))
+			if t.Length() < 1e-6 {
+				t = n.Perpendicular()
+			}
+			t = t.Normalize()
+			b := n.Cross(t)
+			var sum float64
+			for _, k := range kernel {
+				d := t.MulScalar(k.X).Add(b.MulScalar(k.Y)).Add(n.MulScalar(k.Z))
+				q := p.Add(d.MulScalar(s.Radius))
+				c := projection.MulPositionW(q)
+				if c.W <= 0 {
+					continue
+				}
+				sx := int(math.Floor((c.X/c.W + 1) / 2 * float64(w)))
+				sy := int(math.Floor((1 - c.Y/c.W) / 2 * float64(h)))
+				if sx < 0 || sy < 0 || sx >= w || sy >= h {
+					continue
+				}
+				j := sy*w + sx
+				if !ok[j] {
+					continue
+				}
+				z := positions[j].Z
+				if z >= q.Z+s.Bias {
+					// 只计入半径范围内的遮挡物
+					sum += smoothstep(0, 1, s.Radius/math.Abs(p.Z-z))
+				}
+			}
+			occlusion[i] = Clamp(1-s.Strength*sum/float64(len(kernel)), 0, 1)
+		}
+	})
+	if s.BlurRadius > 0 {
+		occlusion = s.blur(dc, occlusion, positions, ok, 1)
+		occlusion = s.blur(dc, occlusion, positions, ok, w)
+	}
+	return occlusion
+}
+
+// blur 沿 step 方向（1 为水平，Width 为垂直）进行双边模糊，深度相差较大的像素不参与混合
+func (s *SSAO) blur(dc *Context, values []float64, positions []Vector, ok []bool, step int) []float64 {
+	w, h := dc.Width, dc.Height
+	r := s.BlurRadius
+	sigma := float64(r)/2 + 0.5
+	depthSigma := s.Radius / 2
+	result := make([]float64, len(values))
+	parallelFor(h, func(y int) {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			if !ok[i] {
+				result[i] = values[i]
+				continue
+			}
+			z := positions[i].Z
+			var sum, total float64
+			for d := -r; d <= r; d++ {
+				nx, ny := x, y
+				if step == 1 {
+					nx += d
+				} else {
+					ny += d
+				}
+				if nx < 0 || ny < 0 || nx >= w || ny >= h {
+					continue
+				}
+				j := i + d*step
+				if !ok[j] {
+					continue
+				}
+				dz := (positions[j].Z - z) / depthSigma
+				fd := float64(d) / sigma
+				weight := math.Exp(-0.5 * (fd*fd + dz*dz))
+				sum += values[j] * weight
+				total += weight
+			}
+			result[i] = sum / total
+		}
+	})
+	return result
+}
+
+// Multiply 将环境光可见度乘到颜色缓冲区中，透明度保持不变
+func (s *SSAO) Multiply(dc *Context, occlusion []float64) {
+	parallelFor(dc.Height, func(y int) {
+		for x := 0; x < dc.Width; x++ {
+			a := occlusion[y*dc.Width+x]
+			if a >= 1 {
+				continue
+			}
+			c := colorFromNRGBA(dc.ColorBuffer.NRGBAAt(x, y))
+			if dc.SRGBOutput {
+				c = c.Linear().MulScalar(a).SRGB().Alpha(c.A)
+			} else {
+				c = c.MulScalar(a).Alpha(c.A)
+			}
+			dc.ColorBuffer.SetNRGBA(x, y, c.NRGBA())
+		}
+	})
+}
+
+// Apply 以后处理的方式计算环境光遮蔽并乘到颜色缓冲区中，法线由深度缓冲区重建
+// projection 是绘制时使用的投影矩阵
+func (s *SSAO) Apply(dc *Context, projection Matrix) {
+	s.Multiply(dc, s.Compute(dc, projection, nil))
+}
@@ -29,52 +29,195 @@ func LoadTexture(path string) (Texture, error) {
 	return NewImageTexture(im), nil
 }
 
+// WrapMode 纹理环绕模式，决定纹理坐标超出 [0, 1] 范围时如何采样
+type WrapMode int
+
+const (
+	_ WrapMode = iota
+	// WrapRepeat 重复
+	WrapRepeat
+	// WrapMirroredRepeat 镜像重复
+	WrapMirroredRepeat
+	// WrapClampToEdge 钳制到边缘纹素
+	WrapClampToEdge
+	// WrapClampToBorder 钳制到边框颜色
+	WrapClampToBorder
+)
+
+// wrapIndex 按环绕模式将纹素索引 i 映射到 [0, n) 范围内
+// 返回 false 表示应使用边框颜色
+func wrapIndex(i, n int, mode WrapMode) (int, bool) {
+	if i >= 0 && i < n {
+		return i, true
+	}
+	switch mode {
+	case WrapMirroredRepeat:
+		i %= 2 * n
+		if i < 0 {
+			i += 2 * n
+		}
+		if i >= n {
+			i = 2*n - 1 - i
+		}
+		return i, true
+	case WrapClampToEdge:
+		return ClampInt(i, 0, n-1), true
+	case WrapClampToBorder:
+		return i, i >= 0 && i < n
+	default:
+		i %= n
+		if i < 0 {
+			i += n
+		}
+		return i, true
+	}
+}
+
+// LoadTextureSRGB 加载 sRGB 编码的纹理，纹素被转换到线性空间
+func LoadTextureSRGB(path string) (Texture, error) {
+	im, err := LoadImage(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewImageTextureSRGB(im), nil
+}
+
 // ImageTexture 纹理对象
+// 使用 NewImageTexture(im).(*ImageTexture) 可以修改环绕模式和边框颜色
 type ImageTexture struct {
-	Width  int
-	Height int
-	Image  image.Image
+	Width       int
+	Height      int
+	Image       image.Image
+	Mipmaps     []*ImageTexture // mipmap 链，第 i 项为第 i+1 级
+	WrapU       WrapMode        // u 方向的环绕模式
+	WrapV       WrapMode        // v 方向的环绕模式
+	BorderColor Color           // WrapClampToBorder 模式下的边框颜色
+	SRGB        bool            // 图像是否为 sRGB 编码，为 true 时纹素已转换到线性空间
+	pix         []float32       // 打包的 RGBA 纹素（预乘透明度），每个纹素 4 个分量
 }
 
-// NewImageTexture 创建纹理对象
+// NewImageTexture 创建纹理对象，默认在两个方向上重复
 // im: 图像对象
 // 返回值: 纹理对象
 func NewImageTexture(im image.Image) Texture {
-	size := im.Bounds().Max
-	return &ImageTexture{size.X, size.Y, im}
+	size := im.Bounds().Size()
+	pix := packImage(im)
+	return &ImageTexture{size.X, size.Y, im, nil, WrapRepeat, WrapRepeat, Transparent, false, pix}
+}
+
+// NewImageTextureSRGB 由 sRGB 编码的图像创建纹理对象，纹素被转换到线性空间
+// 采样结果以及由此生成的 mipmap 都是线性颜色
+func NewImageTextureSRGB(im image.Image) Texture {
+	t := NewImageTexture(im).(*ImageTexture)
+	t.SRGB = true
+	for i := 0; i < len(t.pix); i += 4 {
+		p := t.pix[i : i+4 : i+4]
+		a := float64(p[3])
+		if a == 0 {
+			continue
+		}
+		// 纹素是预乘透明度的，先还原再转换
+		p[0] = float32(srgbToLinear(float64(p[0])/a) * a)
+		p[1] = float32(srgbToLinear(float64(p[1])/a) * a)
+		p[2] = float32(srgbToLinear(float64(p[2])/a) * a)
+	}
+	return t
+}
+
+// packImage 将图像转换为打包的 RGBA 纹素数组，与 MakeColor 的结果一致
+// 纹素 (0, 0) 对应图像边界的左上角 Bounds().Min
+func packImage(im image.Image) []float32 {
+	const d = 0xffff
+	bounds := im.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	pix := make([]float32, w*h*4)
+	i := 0
+	switch im := im.(type) {
+	case *image.NRGBA:
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				j := im.PixOffset(bounds.Min.X+x, bounds.Min.Y+y)
+				a := uint32(im.Pix[j+3]) * 0x101
+				pix[i+0] = float32(uint32(im.Pix[j+0])*0x101*a/d) / d
+				pix[i+1] = float32(uint32(im.Pix[j+1])*0x101*a/d) / d
+				pix[i+2] = float32(uint32(im.Pix[j+2])*0x101*a/d) / d
+				pix[i+3] = float32(a) / d
+				i += 4
+			}
+		}
+	case *image.RGBA:
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				j := im.PixOffset(bounds.Min.X+x, bounds.Min.Y+y)
+				pix[i+0] = float32(uint32(im.Pix[j+0])*0x101) / d
+				pix[i+1] = float32(uint32(im.Pix[j+1])*0x101) / d
+				pix[i+2] = float32(uint32(im.Pix[j+2])*0x101) / d
+				pix[i+3] = float32(uint32(im.Pix[j+3])*0x101) / d
+				i += 4
+			}
+		}
+	default:
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				r, g, b, a := im.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+				pix[i+0] = float32(r) / d
+				pix[i+1] = float32(g) / d
+				pix[i+2] = float32(b) / d
+				pix[i+3] = float32(a) / d
+				i += 4
+			}
+		}
+	}
+	return pix
+}
+
+// SetWrapMode 设置 u 和 v 方向的环绕模式
+func (t *ImageTexture) SetWrapMode(u, v WrapMode) {
+	t.WrapU = u
+	t.WrapV = v
 }
 
 // Sample 对纹理进行采样
 // u, v: 纹理坐标
 // 返回值: 采样得到的颜色
 func (t *ImageTexture) Sample(u, v float64) Color {
-	v = 1 - v
-	u -= math.Floor(u)
-	v -= math.Floor(v)
-	x := int(u * float64(t.Width))
-	y := int(v * float64(t.Height))
-	return MakeColor(t.Image.At(x, y))
+	return t.nearest(t, u, v)
 }
 
 // BilinearSample 使用双线性插值对纹理进行采样
 // u, v: 纹理坐标
 // 返回值: 采样得到的颜色
 func (t *ImageTexture) BilinearSample(u, v float64) Color {
-	v = 1 - v
-	u -= math.Floor(u)
-	v -= math.Floor(v)
-	x := u * float64(t.Width-1)
-	y := v * float64(t.Height-1)
-	x0 := int(x)
-	y0 := int(y)
+	return t.bilinear(t, u, v)
+}
+
+// nearest 对第 level 级纹理进行最近邻采样
+func (t *ImageTexture) nearest(level *ImageTexture, u, v float64) Color {
+	x := int(math.Floor(u * float64(level.Width)))
+	y := int(math.Floor((1 - v) * float64(level.Height)))
+	return t.texel(level, x, y)
+}
+
+// bilinear 对第 level 级纹理进行双线性采样，纹素中心位于半整数坐标处
+func (t *ImageTexture) bilinear(level *ImageTexture, u, v float64) Color {
+	x := u*float64(level.Width) - 0.5
+	y := (1-v)*float64(level.Height) - 0.5
+	fx := math.Floor(x)
+	fy := math.Floor(y)
+	x0 := int(fx)
+	y0 := int(fy)
 	x1 := x0 + 1
 	y1 := y0 + 1
-	x -= float64(x0)
-	y -= float64(y0)
-	c00 := MakeColor(t.Image.At(x0, y0))
-	c01 := MakeColor(t.Image.At(x0, y1))
-	c10 := MakeColor(t.Image.At(x1, y0))
-	c11 := MakeColor(t.Image.At(x1, y1))
+	x -= fx
+	y -= fy
+	x0, okx0 := wrapIndex(x0, level.Width, t.WrapU)
+	x1, okx1 := wrapIndex(x1, level.Width, t.WrapU)
+	y0, oky0 := wrapIndex(y0, level.Height, t.WrapV)
+	y1, oky1 := wrapIndex(y1, level.Height, t.WrapV)
+	c00 := t.fetch(level, x0, y0, okx0 && oky0)
+	c01 := t.fetch(level, x0, y1, okx0 && oky1)
+	c10 := t.fetch(level, x1, y0, okx1 && oky0)
+	c11 := t.fetch(level, x1, y1, okx1 && oky1)
 	c := Color{}
 	c = c.Add(c00.MulScalar((1 - x) * (1 - y)))
 	c = c.Add(c10.MulScalar(x * (1 - y)))
@@ -82,3 +225,30 @@ func (t *ImageTexture) BilinearSample(u, v float64) Color {
 	c = c.Add(c11.MulScalar(x * y))
 	return c
 }
+
+// texel 按环绕模式返回第 level 级纹理中 (x, y) 处的纹素
+func (t *ImageTexture) texel(level *ImageTexture, x, y int) Color {
+	x, ok1 := wrapIndex(x, level.Width, t.WrapU)
+	y, ok2 := wrapIndex(y, level.Height, t.WrapV)
+	return t.fetch(level, x, y, ok1 && ok2)
+}
+
+// fetch 返回第 level 级纹理中已环绕的坐标 (x, y) 处的纹素，ok 为 false 时返回边框颜色
+func (t *ImageTexture) fetch(level *ImageTexture, x, y int, ok bool) Color {
+	if !ok {
+		return t.BorderColor
+	}
+	return level.at(x, y)
+}
+
+// at 返回 (x, y) 处的纹素，坐标必须在纹理范围内
+func (t *ImageTexture) at(x, y int) Color {
+	if t.pix == nil {
+		// 未经 NewImageTexture 创建的纹理
+		min := t.Image.Bounds().Min
+		return MakeColor(t.Image.At(min.X+x, min.Y+y))
+	}
+	i := (y*t.Width + x) * 4
+	p := t.pix[i : i+4 : i+4]
+	return Color{float64(p[0]), float64(p[1]), float64(p[2]), float64(p[3])}
+}
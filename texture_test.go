@@ -0,0 +1,136 @@
+package fauxgl
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+const benchmarkTextureSize = 1024
+
+// testTextureImage 返回带有半透明像素、边界从 (x0, y0) 开始的测试图像
+func testTextureImage(x0, y0, w, h int) *image.NRGBA {
+	im := image.NewNRGBA(image.Rect(x0, y0, x0+w, y0+h))
+	for y := y0; y < y0+h; y++ {
+		for x := x0; x < x0+w; x++ {
+			im.SetNRGBA(x, y, color.NRGBA{uint8(x * 37), uint8(y * 59), uint8(x ^ y), uint8(x*y*13 + 7)})
+		}
+	}
+	return im
+}
+
+// checkTexels 检查纹理的每个纹素与 MakeColor(im.At(...)) 一致
+func checkTexels(t *testing.T, name string, texture *ImageTexture, im image.Image) {
+	t.Helper()
+	bounds := im.Bounds()
+	if texture.Width != bounds.Dx() || texture.Height != bounds.Dy() {
+		t.Fatalf("%s: size %dx%d, expected %dx%d", name, texture.Width, texture.Height, bounds.Dx(), bounds.Dy())
+	}
+	for y := 0; y < texture.Height; y++ {
+		for x := 0; x < texture.Width; x++ {
+			got := texture.at(x, y)
+			want := MakeColor(im.At(bounds.Min.X+x, bounds.Min.Y+y))
+			if math.Abs(got.R-want.R) > 1e-6 || math.Abs(got.G-want.G) > 1e-6 ||
+				math.Abs(got.B-want.B) > 1e-6 || math.Abs(got.A-want.A) > 1e-6 {
+				t.Fatalf("%s: texel (%d, %d) = %v, expected %v", name, x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestImageTextureTexels(t *testing.T) {
+	nrgba := testTextureImage(0, 0, 16, 12)
+	rgba := image.NewRGBA(nrgba.Bounds())
+	gray := image.NewGray16(nrgba.Bounds())
+	for y := 0; y < 12; y++ {
+		for x := 0; x < 16; x++ {
+			rgba.Set(x, y, nrgba.At(x, y))
+			gray.Set(x, y, nrgba.At(x, y))
+		}
+	}
+	offset := testTextureImage(-3, 5, 7, 9)
+	images := []struct {
+		name string
+		im   image.Image
+	}{
+		{"nrgba", nrgba},
+		{"rgba", rgba},
+		{"generic", gray},
+		{"offset", offset},
+		{"nrgba sub-image", nrgba.SubImage(image.Rect(4, 4, 8, 10))},
+		{"rgba sub-image", rgba.SubImage(image.Rect(3, 2, 11, 7))},
+		{"generic sub-image", gray.SubImage(image.Rect(5, 1, 9, 12))},
+	}
+	for _, c := range images {
+		checkTexels(t, c.name, NewImageTexture(c.im).(*ImageTexture), c.im)
+		// 未经 NewImageTexture 创建的纹理直接读取图像
+		bounds := c.im.Bounds()
+		checkTexels(t, c.name+" without texels", &ImageTexture{Width: bounds.Dx(), Height: bounds.Dy(), Image: c.im}, c.im)
+	}
+}
+
+// benchmarkTexture 返回用于基准测试的图像和随机纹理坐标
+func benchmarkTexture() (*image.NRGBA, []Vector) {
+	const size = benchmarkTextureSize
+	im := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			im.SetNRGBA(x, y, color.NRGBA{uint8(x), uint8(y), uint8(x ^ y), 255})
+		}
+	}
+	rnd := rand.New(rand.NewSource(1))
+	uvs := make([]Vector, 4096)
+	for i := range uvs {
+		uvs[i] = V(rnd.Float64(), rnd.Float64(), 0)
+	}
+	return im, uvs
+}
+
+// imageAtBilinearSample 使用 image.Image.At 进行双线性采样，作为对比的基准
+func imageAtBilinearSample(im image.Image, u, v float64) Color {
+	const size = benchmarkTextureSize
+	v = 1 - v
+	u -= math.Floor(u)
+	v -= math.Floor(v)
+	x := u * float64(size-1)
+	y := v * float64(size-1)
+	x0 := int(x)
+	y0 := int(y)
+	x1 := x0 + 1
+	y1 := y0 + 1
+	x -= float64(x0)
+	y -= float64(y0)
+	c00 := MakeColor(im.At(x0, y0))
+	c01 := MakeColor(im.At(x0, y1))
+	c10 := MakeColor(im.At(x1, y0))
+	c11 := MakeColor(im.At(x1, y1))
+	c := Color{}
+	c = c.Add(c00.MulScalar((1 - x) * (1 - y)))
+	c = c.Add(c10.MulScalar(x * (1 - y)))
+	c = c.Add(c01.MulScalar((1 - x) * y))
+	c = c.Add(c11.MulScalar(x * y))
+	return c
+}
+
+func BenchmarkImageAtBilinearSample(b *testing.B) {
+	im, uvs := benchmarkTexture()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		uv := uvs[i%len(uvs)]
+		imageAtBilinearSample(im, uv.X, uv.Y)
+	}
+}
+
+func BenchmarkBilinearSample(b *testing.B) {
+	im, uvs := benchmarkTexture()
+	texture := NewImageTexture(im)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		uv := uvs[i%len(uvs)]
+		texture.BilinearSample(uv.X, uv.Y)
+	}
+}
@@ -0,0 +1,102 @@
+package fauxgl
+
+import "math"
+
+// ToonShader 实现卡通（色阶）着色
+type ToonShader struct {
+	Matrix         Matrix
+	LightDirection Vector
+	ObjectColor    Color
+	AmbientColor   Color
+	DiffuseColor   Color
+	Texture        Texture
+	Ramp           Texture // 一维渐变纹理，非空时代替 Bands 对漫反射进行映射
+	Bands          int     // 漫反射色阶数量
+}
+
+// NewToonShader 创建一个卡通着色器
+func NewToonShader(matrix Matrix, lightDirection Vector, bands int) *ToonShader {
+	ambient := Color{0.3, 0.3, 0.3, 1}
+	diffuse := Color{0.7, 0.7, 0.7, 1}
+	return &ToonShader{
+		matrix, lightDirection,
+		Discard, ambient, diffuse, nil, nil, bands}
+}
+
+// Vertex 顶点着色器
+func (shader *ToonShader) Vertex(v Vertex) Vertex {
+	v.Output = shader.Matrix.MulPositionW(v.Position)
+	return v
+}
+
+// Fragment 片元着色器
+func (shader *ToonShader) Fragment(v Vertex) Color {
+	color := v.Color
+	if shader.ObjectColor != Discard {
+		color = shader.ObjectColor
+	}
+	if shader.Texture != nil {
+		color = shader.Texture.BilinearSample(v.Texture.X, v.Texture.Y)
+	}
+	diffuse := math.Max(v.Normal.Dot(shader.LightDirection), 0)
+	light := shader.AmbientColor
+	if shader.Ramp != nil {
+		// 渐变纹理使用最近邻采样以保留硬边界
+		u := math.Min(diffuse, 1-1e-9)
+		light = light.Add(shader.Ramp.Sample(u, 0.5).Mul(shader.DiffuseColor))
+	} else {
+		light = light.Add(shader.DiffuseColor.MulScalar(quantize(diffuse, shader.Bands)))
+	}
+	return color.Mul(light).Min(White).Alpha(color.A)
+}
+
+// quantize 将 [0, 1] 范围内的值量化为 n 个等级
+func quantize(x float64, n int) float64 {
+	if n <= 1 {
+		if x > 0 {
+			return 1
+		}
+		return 0
+	}
+	i := math.Min(math.Floor(x*float64(n)), float64(n-1))
+	return i / float64(n-1)
+}
+
+// Outline 描边（墨线）参数
+type Outline struct {
+	Color      Color   // 线条颜色
+	Width      float64 // 线宽（像素）
+	Offset     float64 // 轮廓线沿法线外扩的距离
+	SharpAngle float64 // 锐边角度阈值（弧度），<= 0 时不绘制锐边
+	DepthBias  float64 // 深度偏移，使线条绘制在表面之上
+}
+
+// NewOutline 创建描边参数
+func NewOutline(color Color, width float64) *Outline {
+	return &Outline{color, width, 1e-3, Radians(60), -1e-5}
+}
+
+// Mesh 返回网格在视点 eye 下的描边线网格，包括轮廓线和锐边
+func (o *Outline) Mesh(mesh *Mesh, eye Vector) *Mesh {
+	lines := mesh.Silhouette(eye, o.Offset)
+	if o.SharpAngle > 0 {
+		lines.Add(mesh.SharpEdges(o.SharpAngle))
+	}
+	return lines
+}
+
+// DrawOutline 使用变换矩阵 matrix 绘制网格的描边
+// 应在绘制网格本身之后调用，以便深度测试遮挡背面的线条
+func (dc *Context) DrawOutline(mesh *Mesh, matrix Matrix, eye Vector, outline *Outline) RasterizeInfo {
+	shader := dc.Shader
+	lineWidth := dc.LineWidth
+	depthBias := dc.DepthBias
+	dc.Shader = NewSolidColorShader(matrix, outline.Color)
+	dc.LineWidth = outline.Width
+	dc.DepthBias = outline.DepthBias
+	info := dc.DrawMesh(outline.Mesh(mesh, eye))
+	dc.Shader = shader
+	dc.LineWidth = lineWidth
+	dc.DepthBias = depthBias
+	return info
+}
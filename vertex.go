@@ -6,6 +6,7 @@ type Vertex struct {
 	Normal   Vector  // 法向量
 	Texture  Vector  // 纹理坐标
 	Color    Color   // 颜色
+	Scalar   float64 // 标量，例如仿真结果或扫描偏差
 	Output   VectorW // 输出
 }
 
@@ -21,6 +22,7 @@ func InterpolateVertexes(v1, v2, v3 Vertex, b VectorW) Vertex {
 	v.Normal = InterpolateVectors(v1.Normal, v2.Normal, v3.Normal, b).Normalize() // 插值法向量
 	v.Texture = InterpolateVectors(v1.Texture, v2.Texture, v3.Texture, b)         // 插值纹理坐标
 	v.Color = InterpolateColors(v1.Color, v2.Color, v3.Color, b)                  // 插值颜色
+	v.Scalar = InterpolateFloats(v1.Scalar, v2.Scalar, v3.Scalar, b)              // 插值标量
 	v.Output = InterpolateVectorWs(v1.Output, v2.Output, v3.Output, b)            // 插值输出
 	// if v1.Vectors != nil {
 	// 	v.Vectors = make([]Vector, len(v1.Vectors))